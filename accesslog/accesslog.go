@@ -0,0 +1,149 @@
+package accesslog
+
+import (
+	"context"
+	"math/rand"
+	"net/textproto"
+	"strings"
+
+	"github.com/huangsc/blade/auth"
+	"github.com/huangsc/blade/logger"
+)
+
+// Redacted 脱敏后的字段值
+const Redacted = "[REDACTED]"
+
+// Options 访问日志配置选项
+type Options struct {
+	// SampleRate 成功请求的采样率，取值 (0, 1]，默认为 1，失败请求始终记录
+	SampleRate float64
+	// SkipPaths 不记录日志的 HTTP 路径或 gRPC 完整方法名
+	SkipPaths []string
+	// LogHeaders 是否记录请求头或入站元数据
+	LogHeaders bool
+	// RedactHeaders 记录时脱敏的请求头，不区分大小写
+	RedactHeaders []string
+	// RedactFields 记录时脱敏的查询参数
+	RedactFields []string
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithSampleRate 设置成功请求的采样率
+func WithSampleRate(rate float64) Option {
+	return func(o *Options) {
+		o.SampleRate = rate
+	}
+}
+
+// WithSkipPaths 添加不记录日志的 HTTP 路径或 gRPC 完整方法名
+func WithSkipPaths(paths ...string) Option {
+	return func(o *Options) {
+		o.SkipPaths = append(o.SkipPaths, paths...)
+	}
+}
+
+// WithHeaders 设置是否记录请求头或入站元数据
+func WithHeaders(enable bool) Option {
+	return func(o *Options) {
+		o.LogHeaders = enable
+	}
+}
+
+// WithRedactHeaders 添加需要脱敏的请求头
+func WithRedactHeaders(headers ...string) Option {
+	return func(o *Options) {
+		o.RedactHeaders = append(o.RedactHeaders, headers...)
+	}
+}
+
+// WithRedactFields 添加需要脱敏的查询参数
+func WithRedactFields(fields ...string) Option {
+	return func(o *Options) {
+		o.RedactFields = append(o.RedactFields, fields...)
+	}
+}
+
+// newOptions 创建配置选项，默认跳过健康检查与监控指标路径，并脱敏常见凭证请求头
+func newOptions(opts ...Option) *Options {
+	options := &Options{
+		SampleRate: 1,
+		SkipPaths: []string{
+			"/health",
+			"/healthz",
+			"/metrics",
+			"/grpc.health.v1.Health/Check",
+			"/grpc.health.v1.Health/Watch",
+		},
+		RedactHeaders: []string{
+			"Authorization",
+			"Cookie",
+			"Set-Cookie",
+			"X-Api-Key",
+		},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return options
+}
+
+// skip 判断路径是否不记录日志
+func (o *Options) skip(path string) bool {
+	for _, p := range o.SkipPaths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled 判断成功请求是否被采样
+func (o *Options) sampled() bool {
+	return o.SampleRate >= 1 || rand.Float64() < o.SampleRate
+}
+
+// redactHeader 判断请求头是否需要脱敏
+func (o *Options) redactHeader(key string) bool {
+	for _, h := range o.RedactHeaders {
+		if strings.EqualFold(h, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactField 判断查询参数是否需要脱敏
+func (o *Options) redactField(key string) bool {
+	for _, f := range o.RedactFields {
+		if f == key {
+			return true
+		}
+	}
+	return false
+}
+
+// headers 返回脱敏后的请求头
+func (o *Options) headers(header map[string][]string) map[string]string {
+	result := make(map[string]string, len(header))
+	for k, v := range header {
+		if o.redactHeader(k) {
+			result[textproto.CanonicalMIMEHeaderKey(k)] = Redacted
+			continue
+		}
+		result[textproto.CanonicalMIMEHeaderKey(k)] = strings.Join(v, ",")
+	}
+	return result
+}
+
+// userFields 返回用户字段，追踪字段由 logger.Logger 的 WithContext 附加
+func userFields(ctx context.Context, claims *auth.Claims) []logger.Field {
+	if claims == nil {
+		claims, _ = auth.FromContext(ctx)
+	}
+	if claims == nil {
+		return nil
+	}
+	return []logger.Field{logger.String("user_id", claims.UserID)}
+}
@@ -0,0 +1,124 @@
+package accesslog
+
+import (
+	"context"
+	"time"
+
+	"github.com/huangsc/blade/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerInterceptor 创建一元 RPC 访问日志拦截器，每次调用输出一条日志
+//
+// 用户ID从拦截器收到的上下文中获取，需要记录用户ID时应位于认证拦截器之后。
+// 服务端错误以 Error 级别输出，客户端错误以 Warn 级别输出，其余以 Info 级别输出。
+func UnaryServerInterceptor(l logger.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	options := newOptions(opts...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if options.skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		options.log(ctx, l, info.FullMethod, time.Since(start), messageSize(req), messageSize(resp), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 创建流式 RPC 访问日志拦截器，每个流输出一条日志
+//
+// 请求与响应大小为流上收发消息的大小之和。
+func StreamServerInterceptor(l logger.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	options := newOptions(opts...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if options.skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		stream := &sizedServerStream{ServerStream: ss}
+		err := handler(srv, stream)
+		options.log(ss.Context(), l, info.FullMethod, time.Since(start), stream.received, stream.sent, err)
+		return err
+	}
+}
+
+// log 输出 gRPC 调用日志
+func (o *Options) log(ctx context.Context, l logger.Logger, method string, latency time.Duration, reqSize, respSize int, err error) {
+	code := status.Code(err)
+	if code == codes.OK && !o.sampled() {
+		return
+	}
+
+	fields := []logger.Field{
+		logger.String("kind", "grpc"),
+		logger.String("method", method),
+		logger.String("status", code.String()),
+		logger.Duration("latency", latency),
+		logger.Int("request_size", reqSize),
+		logger.Int("response_size", respSize),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, logger.String("peer", p.Addr.String()))
+	}
+	if o.LogHeaders {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			fields = append(fields, logger.Any("headers", o.headers(md)))
+		}
+	}
+	fields = append(fields, userFields(ctx, nil)...)
+	if err != nil {
+		fields = append(fields, logger.Error(err))
+	}
+
+	log := l.WithContext(ctx)
+	switch code {
+	case codes.OK:
+		log.Info("grpc request", fields...)
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition,
+		codes.OutOfRange, codes.ResourceExhausted:
+		log.Warn("grpc request", fields...)
+	default:
+		log.Error("grpc request", fields...)
+	}
+}
+
+// messageSize 返回 protobuf 消息的编码大小，非 protobuf 消息为 0
+func messageSize(msg interface{}) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// sizedServerStream 统计收发消息大小的服务端流
+type sizedServerStream struct {
+	grpc.ServerStream
+	received int
+	sent     int
+}
+
+// RecvMsg 实现 grpc.ServerStream 接口
+func (s *sizedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received += messageSize(m)
+	}
+	return err
+}
+
+// SendMsg 实现 grpc.ServerStream 接口
+func (s *sizedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent += messageSize(m)
+	}
+	return err
+}
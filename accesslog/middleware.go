@@ -0,0 +1,108 @@
+package accesslog
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/auth"
+	"github.com/huangsc/blade/logger"
+)
+
+// Middleware 创建HTTP访问日志中间件，每个请求输出一条日志
+//
+// 状态码为 5xx 时以 Error 级别输出，4xx 以 Warn 级别输出，其余以 Info 级别输出。
+func Middleware(l logger.Logger, opts ...Option) gin.HandlerFunc {
+	options := newOptions(opts...)
+	return func(c *gin.Context) {
+		if options.skip(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		code := c.Writer.Status()
+		if code < http.StatusBadRequest && !options.sampled() {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		fields := []logger.Field{
+			logger.String("kind", "http"),
+			logger.String("method", c.Request.Method),
+			logger.String("route", route),
+			logger.String("path", c.Request.URL.Path),
+			logger.Int("status", code),
+			logger.Duration("latency", latency),
+			logger.Int64("request_size", requestSize(c.Request)),
+			logger.Int("response_size", responseSize(c)),
+			logger.String("peer", c.ClientIP()),
+		}
+		if query := options.query(c.Request.URL.Query()); query != "" {
+			fields = append(fields, logger.String("query", query))
+		}
+		if options.LogHeaders {
+			fields = append(fields, logger.Any("headers", options.headers(c.Request.Header)))
+		}
+		fields = append(fields, userFields(c.Request.Context(), ginClaims(c))...)
+		if err := c.Errors.Last(); err != nil {
+			fields = append(fields, logger.Error(err.Err))
+		}
+
+		log := l.WithContext(c.Request.Context())
+		switch {
+		case code >= http.StatusInternalServerError:
+			log.Error("http request", fields...)
+		case code >= http.StatusBadRequest:
+			log.Warn("http request", fields...)
+		default:
+			log.Info("http request", fields...)
+		}
+	}
+}
+
+// query 返回脱敏后的查询参数
+func (o *Options) query(values url.Values) string {
+	if len(values) == 0 {
+		return ""
+	}
+	for k := range values {
+		if o.redactField(k) {
+			values[k] = []string{Redacted}
+		}
+	}
+	return values.Encode()
+}
+
+// ginClaims 获取认证中间件保存的声明
+func ginClaims(c *gin.Context) *auth.Claims {
+	if v, ok := c.Get(string(auth.ClaimsKey)); ok {
+		if claims, ok := v.(*auth.Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
+// requestSize 返回请求体大小，未知时为 0
+func requestSize(r *http.Request) int64 {
+	if r.ContentLength < 0 {
+		return 0
+	}
+	return r.ContentLength
+}
+
+// responseSize 返回响应体大小，未写入时为 0
+func responseSize(c *gin.Context) int {
+	if size := c.Writer.Size(); size > 0 {
+		return size
+	}
+	return 0
+}
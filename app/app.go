@@ -0,0 +1,347 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/huangsc/blade/config"
+	"github.com/huangsc/blade/logger"
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/server"
+	"github.com/huangsc/blade/tracing"
+)
+
+// App 应用生命周期管理器
+//
+// App 负责并发启动所有服务器、监听退出信号、按顺序执行生命周期钩子，
+// 并在启动后向注册中心注册、退出前从注册中心注销。
+type App struct {
+	opts       *Options
+	ctx        context.Context
+	cancel     context.CancelFunc
+	mu         sync.Mutex
+	instance   *registry.ServiceInstance
+	registered bool
+}
+
+// appKey 应用上下文键
+type appKey struct{}
+
+// NewContext 创建带有应用信息的上下文
+func NewContext(ctx context.Context, a *App) context.Context {
+	return context.WithValue(ctx, appKey{}, a)
+}
+
+// FromContext 从上下文中获取应用信息
+func FromContext(ctx context.Context) (*App, bool) {
+	a, ok := ctx.Value(appKey{}).(*App)
+	return a, ok
+}
+
+// New 创建应用
+func New(opts ...Option) *App {
+	options := &Options{
+		Context:         context.Background(),
+		Signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		RegistryTimeout: time.Second * 10,
+		StopTimeout:     time.Second * 10,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	if options.ID == "" {
+		options.ID = defaultID()
+	}
+	if options.Logger == nil {
+		options.Logger = logger.NewZapLogger()
+	}
+
+	ctx, cancel := context.WithCancel(options.Context)
+	return &App{
+		opts:   options,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// ID 返回实例ID
+func (a *App) ID() string {
+	return a.opts.ID
+}
+
+// Name 返回服务名称
+func (a *App) Name() string {
+	return a.opts.Name
+}
+
+// Version 返回服务版本
+func (a *App) Version() string {
+	return a.opts.Version
+}
+
+// Metadata 返回服务元数据
+func (a *App) Metadata() map[string]string {
+	return a.opts.Metadata
+}
+
+// Endpoints 返回已注册的服务地址列表
+func (a *App) Endpoints() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.instance != nil {
+		return a.instance.Endpoints
+	}
+	return nil
+}
+
+// Config 返回配置中心
+func (a *App) Config() config.Config {
+	return a.opts.Config
+}
+
+// Tracer 返回追踪器
+func (a *App) Tracer() tracing.Tracer {
+	return a.opts.Tracer
+}
+
+// Logger 返回日志记录器
+func (a *App) Logger() logger.Logger {
+	return a.opts.Logger
+}
+
+// Run 启动应用并阻塞，直到收到退出信号、调用 Stop 或任一服务器异常退出
+func (a *App) Run() error {
+	instance, err := a.buildInstance()
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.instance = instance
+	a.mu.Unlock()
+
+	ctx := NewContext(a.ctx, a)
+	log := a.opts.Logger.WithFields(
+		logger.String("service.id", a.opts.ID),
+		logger.String("service.name", a.opts.Name),
+		logger.String("service.version", a.opts.Version),
+	)
+
+	// 加载配置
+	if a.opts.Config != nil {
+		if err := a.opts.Config.Load(); err != nil {
+			return fmt.Errorf("app: load config: %w", err)
+		}
+	}
+
+	// 执行启动前钩子
+	for _, fn := range a.opts.BeforeStart {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+
+	// 在启动服务器前监听退出信号，启动期间收到的信号同样触发停止
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, a.opts.Signals...)
+	defer signal.Stop(sigCh)
+
+	// 并发启动所有服务器
+	errCh := make(chan error, len(a.opts.Servers))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, srv := range a.opts.Servers {
+		srv := srv
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	log.Info("app started", logger.Any("endpoints", instance.Endpoints))
+
+	// 注册服务
+	var runErr error
+	if a.opts.Registry != nil {
+		rctx, rcancel := context.WithTimeout(ctx, a.opts.RegistryTimeout)
+		runErr = a.opts.Registry.Register(rctx, instance)
+		rcancel()
+		if runErr != nil {
+			runErr = fmt.Errorf("app: register service: %w", runErr)
+		} else {
+			a.mu.Lock()
+			a.registered = true
+			a.mu.Unlock()
+		}
+	}
+
+	// 执行启动后钩子
+	if runErr == nil {
+		for _, fn := range a.opts.AfterStart {
+			if runErr = fn(ctx); runErr != nil {
+				break
+			}
+		}
+	}
+
+	// 等待退出
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+		case sig := <-sigCh:
+			log.Info("app received signal", logger.String("signal", sig.String()))
+		case runErr = <-errCh:
+			log.Error("app server exited", logger.Error(runErr))
+		}
+	}
+
+	stopErr := a.shutdown(log, done)
+	log.Info("app stopped")
+
+	return errors.Join(runErr, stopErr)
+}
+
+// Stop 停止应用
+func (a *App) Stop() error {
+	a.cancel()
+	return nil
+}
+
+// shutdown 标记实例准备下线、执行停止前钩子、注销服务、停止服务器并执行停止后钩子
+func (a *App) shutdown(log logger.Logger, done <-chan struct{}) error {
+	ctx, cancel := context.WithTimeout(NewContext(context.Background(), a), a.opts.StopTimeout)
+	defer cancel()
+
+	var errs []error
+
+	// 标记为准备下线，等待客户端摘除实例
+	a.mu.Lock()
+	instance, registered := a.instance, a.registered
+	a.mu.Unlock()
+	if registered {
+		rctx, rcancel := context.WithTimeout(ctx, a.opts.RegistryTimeout)
+		err := a.opts.Registry.SetStatus(rctx, instance, registry.StatusDraining)
+		rcancel()
+		if err != nil {
+			log.Warn("app set draining status failed", logger.Error(err))
+		} else if a.opts.DrainDelay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(a.opts.DrainDelay):
+			}
+		}
+	}
+
+	// 执行停止前钩子
+	for _, fn := range a.opts.BeforeStop {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 注销服务
+	a.mu.Lock()
+	registered = a.registered
+	a.registered = false
+	a.mu.Unlock()
+	if registered {
+		rctx, rcancel := context.WithTimeout(ctx, a.opts.RegistryTimeout)
+		if err := a.opts.Registry.Deregister(rctx, instance); err != nil {
+			errs = append(errs, fmt.Errorf("app: deregister service: %w", err))
+		}
+		rcancel()
+	}
+
+	// 并发停止所有服务器，超时后不再等待未返回的服务器
+	stopCh := make(chan error, len(a.opts.Servers))
+	for _, srv := range a.opts.Servers {
+		srv := srv
+		go func() {
+			stopCh <- srv.Stop(ctx)
+		}()
+	}
+	timeout := false
+	for i := 0; i < len(a.opts.Servers) && !timeout; i++ {
+		select {
+		case err := <-stopCh:
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-ctx.Done():
+			timeout = true
+		}
+	}
+
+	// 等待服务器退出
+	if !timeout {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			timeout = true
+		}
+	}
+	if timeout {
+		log.Warn("app stop timeout", logger.Duration("timeout", a.opts.StopTimeout))
+		errs = append(errs, ctx.Err())
+	}
+
+	// 执行停止后钩子
+	for _, fn := range a.opts.AfterStop {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// buildInstance 构建注册到注册中心的服务实例
+func (a *App) buildInstance() (*registry.ServiceInstance, error) {
+	endpoints := make([]string, 0, len(a.opts.Endpoints))
+	endpoints = append(endpoints, a.opts.Endpoints...)
+	if len(endpoints) == 0 {
+		for _, srv := range a.opts.Servers {
+			e, ok := srv.(server.Endpointer)
+			if !ok {
+				continue
+			}
+			endpoint, err := e.Endpoint()
+			if err != nil {
+				return nil, err
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return &registry.ServiceInstance{
+		ID:        a.opts.ID,
+		Name:      a.opts.Name,
+		Version:   a.opts.Version,
+		Metadata:  a.opts.Metadata,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// defaultID 生成默认实例ID
+func defaultID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/huangsc/blade/config"
+	"github.com/huangsc/blade/logger"
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/server"
+	"github.com/huangsc/blade/tracing"
+)
+
+// Hook 定义生命周期钩子函数
+type Hook func(ctx context.Context) error
+
+// Options 应用配置选项
+type Options struct {
+	ID              string            // 实例ID
+	Name            string            // 服务名称
+	Version         string            // 服务版本
+	Metadata        map[string]string // 服务元数据
+	Endpoints       []string          // 服务地址列表，为空时从服务器中获取
+	Context         context.Context   // 根上下文
+	Signals         []os.Signal       // 触发退出的信号
+	Servers         []server.Server   // 服务器列表
+	Registry        registry.Registry // 注册中心
+	RegistryTimeout time.Duration     // 注册与注销超时时间
+	DrainDelay      time.Duration     // 停止时标记为 StatusDraining 后等待客户端摘除实例的时间
+	Config          config.Config     // 配置中心
+	Tracer          tracing.Tracer    // 追踪器
+	Logger          logger.Logger     // 日志记录器
+	StopTimeout     time.Duration     // 优雅关闭超时时间
+	BeforeStart     []Hook            // 启动前钩子，按添加顺序执行
+	AfterStart      []Hook            // 启动后钩子，按添加顺序执行
+	BeforeStop      []Hook            // 停止前钩子，按添加顺序执行
+	AfterStop       []Hook            // 停止后钩子，按添加顺序执行
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithID 设置实例ID
+func WithID(id string) Option {
+	return func(o *Options) {
+		o.ID = id
+	}
+}
+
+// WithName 设置服务名称
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+// WithVersion 设置服务版本
+func WithVersion(version string) Option {
+	return func(o *Options) {
+		o.Version = version
+	}
+}
+
+// WithMetadata 设置服务元数据
+func WithMetadata(metadata map[string]string) Option {
+	return func(o *Options) {
+		o.Metadata = metadata
+	}
+}
+
+// WithEndpoints 设置服务地址列表
+func WithEndpoints(endpoints ...string) Option {
+	return func(o *Options) {
+		o.Endpoints = endpoints
+	}
+}
+
+// WithContext 设置根上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) {
+		o.Context = ctx
+	}
+}
+
+// WithSignals 设置触发退出的信号
+func WithSignals(signals ...os.Signal) Option {
+	return func(o *Options) {
+		o.Signals = signals
+	}
+}
+
+// WithServers 添加服务器
+func WithServers(servers ...server.Server) Option {
+	return func(o *Options) {
+		o.Servers = append(o.Servers, servers...)
+	}
+}
+
+// WithRegistry 设置注册中心
+func WithRegistry(r registry.Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+// WithRegistryTimeout 设置注册与注销超时时间
+func WithRegistryTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.RegistryTimeout = timeout
+	}
+}
+
+// WithDrainDelay 设置停止时标记为 registry.StatusDraining 后等待的时间，之后再注销服务并停止服务器
+func WithDrainDelay(delay time.Duration) Option {
+	return func(o *Options) {
+		o.DrainDelay = delay
+	}
+}
+
+// WithConfig 设置配置中心
+func WithConfig(c config.Config) Option {
+	return func(o *Options) {
+		o.Config = c
+	}
+}
+
+// WithTracer 设置追踪器
+func WithTracer(t tracing.Tracer) Option {
+	return func(o *Options) {
+		o.Tracer = t
+	}
+}
+
+// WithLogger 设置日志记录器
+func WithLogger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithStopTimeout 设置优雅关闭超时时间
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.StopTimeout = timeout
+	}
+}
+
+// BeforeStart 添加启动前钩子
+func BeforeStart(hooks ...Hook) Option {
+	return func(o *Options) {
+		o.BeforeStart = append(o.BeforeStart, hooks...)
+	}
+}
+
+// AfterStart 添加启动后钩子
+func AfterStart(hooks ...Hook) Option {
+	return func(o *Options) {
+		o.AfterStart = append(o.AfterStart, hooks...)
+	}
+}
+
+// BeforeStop 添加停止前钩子
+func BeforeStop(hooks ...Hook) Option {
+	return func(o *Options) {
+		o.BeforeStop = append(o.BeforeStop, hooks...)
+	}
+}
+
+// AfterStop 添加停止后钩子
+func AfterStop(hooks ...Hook) Option {
+	return func(o *Options) {
+		o.AfterStop = append(o.AfterStop, hooks...)
+	}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+)
+
+// HeaderAPIKey API密钥请求头
+const HeaderAPIKey = "X-API-Key"
+
+// ErrInvalidAPIKey 无效的API密钥
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyLookup 根据API密钥查找声明，密钥不存在时返回 ErrInvalidAPIKey
+type APIKeyLookup func(ctx context.Context, key string) (*Claims, error)
+
+// StaticAPIKeys 返回基于固定映射的API密钥查找函数
+//
+// 密钥以 SHA-256 摘要保存，查找时不直接比较明文。
+func StaticAPIKeys(keys map[string]*Claims) APIKeyLookup {
+	hashed := make(map[[sha256.Size]byte]*Claims, len(keys))
+	for key, claims := range keys {
+		hashed[sha256.Sum256([]byte(key))] = claims
+	}
+
+	return func(ctx context.Context, key string) (*Claims, error) {
+		claims, ok := hashed[sha256.Sum256([]byte(key))]
+		if !ok {
+			return nil, ErrInvalidAPIKey
+		}
+		return claims, nil
+	}
+}
+
+// APIKeyAuthenticator API密钥认证器
+type APIKeyAuthenticator struct {
+	lookup APIKeyLookup
+	header string
+}
+
+// NewAPIKeyAuthenticator 创建API密钥认证器，header 为空时使用 X-API-Key
+func NewAPIKeyAuthenticator(lookup APIKeyLookup, header string) *APIKeyAuthenticator {
+	if header == "" {
+		header = HeaderAPIKey
+	}
+	return &APIKeyAuthenticator{
+		lookup: lookup,
+		header: header,
+	}
+}
+
+// Authenticate 验证请求中的API密钥
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, req *Request) (*Claims, error) {
+	key := req.Header.Get(a.header)
+	if key == "" {
+		return nil, ErrMissingToken
+	}
+
+	claims, err := a.lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return cloneClaims(claims), nil
+}
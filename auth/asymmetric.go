@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWKSPath JWKS文档的默认挂载路径
+const JWKSPath = "/.well-known/jwks.json"
+
+// AsymmetricAuthenticator 非对称JWT认证器
+//
+// 使用 RSA、ECDSA 或 Ed25519 私钥签名，并根据JWT头部的 kid 选择验证密钥。
+// 认证器可同时持有多个验证密钥，轮换签名密钥时旧密钥仍可用于验证，
+// 直到调用 RemoveKey 将其移除。
+type AsymmetricAuthenticator struct {
+	mu         sync.RWMutex
+	signingKey *Key
+	keys       map[string]*Key
+	expiration time.Duration
+	opts       *JWTOptions
+}
+
+// NewAsymmetricAuthenticator 创建非对称JWT认证器
+func NewAsymmetricAuthenticator(signingKey *Key, expiration time.Duration, opts ...JWTOption) (*AsymmetricAuthenticator, error) {
+	a := &AsymmetricAuthenticator{
+		keys:       make(map[string]*Key),
+		expiration: expiration,
+		opts:       newJWTOptions(opts...),
+	}
+	if err := a.Rotate(signingKey); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GenerateToken 使用当前签名密钥生成JWT令牌
+func (a *AsymmetricAuthenticator) GenerateToken(claims Claims) (string, error) {
+	a.mu.RLock()
+	key := a.signingKey
+	a.mu.RUnlock()
+
+	method, err := key.signingMethod()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, newJWTClaims(claims, a.expiration, a.opts))
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// ValidateToken 根据 kid 选择验证密钥并验证JWT令牌，刷新令牌返回 ErrInvalidToken
+func (a *AsymmetricAuthenticator) ValidateToken(tokenString string) (*Claims, error) {
+	return accessClaims(a.parseToken(tokenString))
+}
+
+// parseToken 验证任意类型的JWT令牌
+func (a *AsymmetricAuthenticator) parseToken(tokenString string) (*Claims, error) {
+	return parseToken(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		a.mu.RLock()
+		key, ok := a.keys[kid]
+		a.mu.RUnlock()
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+
+		return verificationKey(token, key)
+	}, a.opts)
+}
+
+// Rotate 设置新的签名密钥，原签名密钥保留为验证密钥
+func (a *AsymmetricAuthenticator) Rotate(signingKey *Key) error {
+	if signingKey == nil || signingKey.PrivateKey == nil {
+		return fmt.Errorf("%w: signing key requires private key", ErrUnsupportedKey)
+	}
+	if signingKey.ID == "" {
+		return fmt.Errorf("%w: signing key requires id", ErrUnsupportedKey)
+	}
+	if _, err := signingKey.signingMethod(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.signingKey = signingKey
+	a.keys[signingKey.ID] = signingKey
+	return nil
+}
+
+// AddKey 添加验证密钥
+func (a *AsymmetricAuthenticator) AddKey(keys ...*Key) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, key := range keys {
+		a.keys[key.ID] = key
+	}
+}
+
+// RemoveKey 移除验证密钥，当前签名密钥不能被移除
+func (a *AsymmetricAuthenticator) RemoveKey(kid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.signingKey != nil && a.signingKey.ID == kid {
+		return
+	}
+	delete(a.keys, kid)
+}
+
+// JWKS 返回所有验证密钥的JWKS文档
+func (a *AsymmetricAuthenticator) JWKS() (JWKSet, error) {
+	a.mu.RLock()
+	keys := make([]*Key, 0, len(a.keys))
+	for _, key := range a.keys {
+		keys = append(keys, key)
+	}
+	a.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		jwk, err := key.JWK()
+		if err != nil {
+			return JWKSet{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// JWKSHandler 返回发布JWKS文档的处理函数，通常挂载在 JWKSPath
+func (a *AsymmetricAuthenticator) JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set, err := a.JWKS()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, set)
+	}
+}
+
+// verificationKey 检查令牌算法与密钥是否匹配并返回验证用公钥
+func verificationKey(token *jwt.Token, key *Key) (interface{}, error) {
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm %s", ErrInvalidToken, token.Method.Alg())
+	}
+	return key.PublicKey, nil
+}
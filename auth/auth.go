@@ -3,6 +3,8 @@ package auth
 import (
 	"context"
 	"errors"
+
+	"github.com/huangsc/blade/logger"
 )
 
 var (
@@ -14,20 +16,88 @@ var (
 	ErrExpiredToken = errors.New("token expired")
 	// ErrInvalidClaims 无效的声明
 	ErrInvalidClaims = errors.New("invalid claims")
+	// ErrTokenReused 刷新令牌被重复使用
+	ErrTokenReused = errors.New("refresh token reused")
+)
+
+const (
+	// TokenTypeAccess 访问令牌
+	TokenTypeAccess = "access"
+	// TokenTypeRefresh 刷新令牌
+	TokenTypeRefresh = "refresh"
 )
 
 // Claims 令牌声明
 type Claims struct {
+	// ID 令牌ID，对应JWT的 jti
+	ID string `json:"token_id"`
+	// TokenType 令牌类型，为空时视为访问令牌
+	TokenType string `json:"token_type"`
+	// FamilyID 令牌族ID，同一次登录签发的令牌共享该ID
+	FamilyID string `json:"family_id"`
 	// UserID 用户ID
 	UserID string `json:"user_id"`
 	// Username 用户名
 	Username string `json:"username"`
 	// Role 角色
 	Role string `json:"role"`
-	// ExpiresAt 过期时间
+	// Roles 角色列表
+	Roles []string `json:"roles"`
+	// Scopes 授权范围，JWT中以空格分隔的 scope 声明表示
+	Scopes []string `json:"scopes"`
+	// TenantID 租户ID
+	TenantID string `json:"tenant_id"`
+	// Issuer 签发者，为空时使用认证器配置的签发者
+	Issuer string `json:"issuer"`
+	// Subject 主题，为空时使用 UserID
+	Subject string `json:"subject"`
+	// Audience 受众，为空时使用认证器配置的受众
+	Audience []string `json:"audience"`
+	// ExpiresAt 过期时间，签发时非零则使用该值代替默认过期时间
 	ExpiresAt int64 `json:"expires_at"`
+	// NotBefore 生效时间
+	NotBefore int64 `json:"not_before"`
 	// IssuedAt 签发时间
 	IssuedAt int64 `json:"issued_at"`
+	// Extra 自定义声明，与标准声明一起编码到JWT中
+	Extra map[string]interface{} `json:"extra"`
+}
+
+// HasRole 检查是否拥有指定角色
+func (c *Claims) HasRole(role string) bool {
+	if c.Role == role {
+		return true
+	}
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope 检查是否拥有指定授权范围
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Get 获取自定义声明
+func (c *Claims) Get(key string) (interface{}, bool) {
+	v, ok := c.Extra[key]
+	return v, ok
+}
+
+// Set 设置自定义声明
+func (c *Claims) Set(key string, value interface{}) {
+	if c.Extra == nil {
+		c.Extra = make(map[string]interface{})
+	}
+	c.Extra[key] = value
 }
 
 // Authenticator 认证器接口
@@ -38,12 +108,49 @@ type Authenticator interface {
 	ValidateToken(token string) (*Claims, error)
 }
 
+// ContextAuthenticator 支持上下文的认证器
+//
+// 中间件和拦截器优先使用该接口验证令牌，以便将请求上下文传递给
+// 撤销列表等需要访问外部存储的组件。
+type ContextAuthenticator interface {
+	// ValidateTokenContext 验证令牌
+	ValidateTokenContext(ctx context.Context, token string) (*Claims, error)
+}
+
+// validateToken 使用认证器验证令牌
+func validateToken(ctx context.Context, auth Authenticator, token string) (*Claims, error) {
+	if ca, ok := auth.(ContextAuthenticator); ok {
+		return ca.ValidateTokenContext(ctx, token)
+	}
+	return auth.ValidateToken(token)
+}
+
+// tokenParser 可解析任意类型令牌的认证器
+//
+// 认证器的 ValidateToken 只接受访问令牌，TokenManager 通过该接口验证刷新令牌。
+type tokenParser interface {
+	parseToken(token string) (*Claims, error)
+}
+
+// accessClaims 拒绝刷新令牌，刷新令牌只能用于 TokenManager.Refresh
+func accessClaims(claims *Claims, err error) (*Claims, error) {
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType == TokenTypeRefresh {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
 // ContextKey 上下文键类型
 type ContextKey string
 
 const (
 	// ClaimsKey 声明上下文键
 	ClaimsKey ContextKey = "claims"
+	// TokenKey 原始令牌上下文键
+	TokenKey ContextKey = "token"
 )
 
 // FromContext 从上下文中获取声明
@@ -56,3 +163,27 @@ func FromContext(ctx context.Context) (*Claims, bool) {
 func NewContext(ctx context.Context, claims *Claims) context.Context {
 	return context.WithValue(ctx, ClaimsKey, claims)
 }
+
+// LogFields 提取上下文中声明的 user_id 与 tenant_id，可作为 logger.ContextExtractor 使用
+func LogFields(ctx context.Context) []logger.Field {
+	claims, ok := FromContext(ctx)
+	if !ok || claims == nil {
+		return nil
+	}
+	fields := []logger.Field{logger.String("user_id", claims.UserID)}
+	if claims.TenantID != "" {
+		fields = append(fields, logger.String("tenant_id", claims.TenantID))
+	}
+	return fields
+}
+
+// TokenFromContext 从上下文中获取调用方的原始令牌
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(TokenKey).(string)
+	return token, ok && token != ""
+}
+
+// NewTokenContext 创建带有原始令牌的上下文
+func NewTokenContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, TokenKey, token)
+}
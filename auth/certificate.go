@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+)
+
+// ErrInvalidCertificate 无效的客户端证书
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+// CertificateMapper 将已验证的客户端证书转换为声明
+type CertificateMapper func(cert *x509.Certificate) (*Claims, error)
+
+// CertificateAuthenticator 基于双向TLS客户端证书的认证器
+//
+// 只接受在TLS握手中已通过CA验证的证书，服务端需使用
+// tls.RequireAndVerifyClientCert 或 tls.VerifyClientCertIfGiven 模式。
+type CertificateAuthenticator struct {
+	mapper CertificateMapper
+}
+
+// NewCertificateAuthenticator 创建客户端证书认证器，mapper 为 nil 时使用 DefaultCertificateMapper
+func NewCertificateAuthenticator(mapper CertificateMapper) *CertificateAuthenticator {
+	if mapper == nil {
+		mapper = DefaultCertificateMapper
+	}
+	return &CertificateAuthenticator{mapper: mapper}
+}
+
+// Authenticate 根据已验证的客户端证书认证请求
+func (a *CertificateAuthenticator) Authenticate(ctx context.Context, req *Request) (*Claims, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, ErrMissingToken
+	}
+	if len(req.TLS.VerifiedChains) == 0 {
+		return nil, ErrInvalidCertificate
+	}
+	return a.mapper(req.TLS.VerifiedChains[0][0])
+}
+
+// DefaultCertificateMapper 默认的证书声明映射
+//
+// 身份优先使用第一个 URI 类型的 SAN(如 SPIFFE ID)，其次使用第一个 DNS SAN，
+// 最后使用主题的 CN。Username 为主题的 CN，证书的 SAN 和组织单位保存在自定义声明中。
+func DefaultCertificateMapper(cert *x509.Certificate) (*Claims, error) {
+	id := cert.Subject.CommonName
+	switch {
+	case len(cert.URIs) > 0:
+		id = cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		id = cert.DNSNames[0]
+	}
+	if id == "" {
+		return nil, ErrInvalidCertificate
+	}
+
+	claims := &Claims{
+		UserID:    id,
+		Username:  cert.Subject.CommonName,
+		Subject:   id,
+		Issuer:    cert.Issuer.CommonName,
+		ExpiresAt: cert.NotAfter.Unix(),
+		NotBefore: cert.NotBefore.Unix(),
+	}
+
+	uris := make([]string, 0, len(cert.URIs))
+	for _, u := range cert.URIs {
+		uris = append(uris, u.String())
+	}
+	if len(uris) > 0 {
+		claims.Set("uris", uris)
+	}
+	if len(cert.DNSNames) > 0 {
+		claims.Set("dns_names", cert.DNSNames)
+	}
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		claims.Set("organizational_units", cert.Subject.OrganizationalUnit)
+	}
+	return claims, nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"google.golang.org/grpc"
@@ -10,32 +11,93 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// HealthMethods gRPC 健康检查服务的方法
+	HealthMethods = "/grpc.health.v1.Health/*"
+	// ReflectionMethods gRPC 反射服务的方法
+	ReflectionMethods = "/grpc.reflection.*"
+)
+
+// InterceptorOptions 认证拦截器配置选项
+//
+// 方法名使用完整方法名(如 /user.v1.UserService/GetUser)，以 * 结尾时按前缀匹配
+// (如 /user.v1.UserService/*)。
+type InterceptorOptions struct {
+	SkipMethods     []string      // 跳过认证的方法
+	OptionalMethods []string      // 可选认证的方法，携带令牌时验证并附加声明
+	MethodRules     []*MethodRule // 方法级别的角色与授权范围要求
+}
+
+// MethodRule 方法级别的访问要求
+type MethodRule struct {
+	Method string   // 方法名
+	Roles  []string // 允许的角色，拥有其中任意一个即可
+	Scopes []string // 要求的授权范围，需要全部拥有
+}
+
+// InterceptorOption 定义认证拦截器配置函数类型
+type InterceptorOption func(*InterceptorOptions)
+
+// WithSkipMethods 设置跳过认证的方法
+func WithSkipMethods(methods ...string) InterceptorOption {
+	return func(o *InterceptorOptions) {
+		o.SkipMethods = append(o.SkipMethods, methods...)
+	}
+}
+
+// WithOptionalMethods 设置可选认证的方法
+func WithOptionalMethods(methods ...string) InterceptorOption {
+	return func(o *InterceptorOptions) {
+		o.OptionalMethods = append(o.OptionalMethods, methods...)
+	}
+}
+
+// WithMethodRoles 设置方法要求的角色
+func WithMethodRoles(method string, roles ...string) InterceptorOption {
+	return func(o *InterceptorOptions) {
+		o.MethodRules = append(o.MethodRules, &MethodRule{Method: method, Roles: roles})
+	}
+}
+
+// WithMethodScopes 设置方法要求的授权范围
+func WithMethodScopes(method string, scopes ...string) InterceptorOption {
+	return func(o *InterceptorOptions) {
+		o.MethodRules = append(o.MethodRules, &MethodRule{Method: method, Scopes: scopes})
+	}
+}
+
 // UnaryServerInterceptor 创建一元 RPC 认证拦截器
-func UnaryServerInterceptor(auth Authenticator) grpc.UnaryServerInterceptor {
+func UnaryServerInterceptor(auth Authenticator, opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	return UnaryServerInterceptorWithAuthenticator(BearerAuthenticator(auth), opts...)
+}
+
+// StreamServerInterceptor 创建流式 RPC 认证拦截器
+func StreamServerInterceptor(auth Authenticator, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	return StreamServerInterceptorWithAuthenticator(BearerAuthenticator(auth), opts...)
+}
+
+// UnaryServerInterceptorWithAuthenticator 创建基于请求认证器的一元 RPC 认证拦截器
+func UnaryServerInterceptorWithAuthenticator(auth RequestAuthenticator, opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	options := newInterceptorOptions(opts...)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// 从元数据中获取令牌
-		claims, err := authenticateRequest(ctx, auth)
+		newCtx, err := options.authenticate(ctx, auth, newGRPCRequest(ctx, info.FullMethod, req))
 		if err != nil {
 			return nil, err
 		}
-
-		// 将认证信息添加到上下文
-		newCtx := NewContext(ctx, claims)
 		return handler(newCtx, req)
 	}
 }
 
-// StreamServerInterceptor 创建流式 RPC 认证拦截器
-func StreamServerInterceptor(auth Authenticator) grpc.StreamServerInterceptor {
+// StreamServerInterceptorWithAuthenticator 创建基于请求认证器的流式 RPC 认证拦截器
+func StreamServerInterceptorWithAuthenticator(auth RequestAuthenticator, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	options := newInterceptorOptions(opts...)
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		// 从元数据中获取令牌
-		claims, err := authenticateRequest(ss.Context(), auth)
+		ctx := ss.Context()
+		newCtx, err := options.authenticate(ctx, auth, newGRPCRequest(ctx, info.FullMethod, nil))
 		if err != nil {
 			return err
 		}
 
-		// 将认证信息添加到上下文
-		newCtx := NewContext(ss.Context(), claims)
 		wrappedStream := &wrappedServerStream{
 			ServerStream: ss,
 			ctx:          newCtx,
@@ -44,33 +106,101 @@ func StreamServerInterceptor(auth Authenticator) grpc.StreamServerInterceptor {
 	}
 }
 
-// authenticateRequest 验证请求中的令牌
-func authenticateRequest(ctx context.Context, auth Authenticator) (*Claims, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, ErrMissingToken.Error())
-	}
-
-	// 从元数据中获取令牌
-	values := md.Get("authorization")
-	if len(values) == 0 {
-		return nil, status.Error(codes.Unauthenticated, ErrMissingToken.Error())
+// newInterceptorOptions 创建认证拦截器配置
+func newInterceptorOptions(opts ...InterceptorOption) *InterceptorOptions {
+	options := &InterceptorOptions{}
+	for _, o := range opts {
+		o(options)
 	}
+	return options
+}
 
-	// 解析令牌
-	authHeader := values[0]
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return nil, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+// authenticate 按方法配置认证请求，并将声明添加到上下文
+func (o *InterceptorOptions) authenticate(ctx context.Context, auth RequestAuthenticator, req *Request) (context.Context, error) {
+	method := req.Path
+	if matchAnyMethod(o.SkipMethods, method) {
+		return ctx, nil
 	}
 
-	// 验证令牌
-	claims, err := auth.ValidateToken(parts[1])
+	// 认证请求
+	claims, err := auth.Authenticate(ctx, req)
 	if err != nil {
+		// 可选认证的方法在匹配访问要求时仍需认证
+		if errors.Is(err, ErrMissingToken) && matchAnyMethod(o.OptionalMethods, method) && !o.hasMethodRule(method) {
+			return ctx, nil
+		}
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
-	return claims, nil
+	// 检查方法级别的访问要求
+	for _, rule := range o.MethodRules {
+		if !matchMethod(rule.Method, method) {
+			continue
+		}
+		if err := rule.check(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	// 将认证信息和原始令牌添加到上下文
+	newCtx := NewContext(ctx, claims)
+	if token, ok := requestToken(req); ok {
+		newCtx = NewTokenContext(newCtx, token)
+	}
+	return newCtx, nil
+}
+
+// hasMethodRule 检查方法是否有访问要求
+func (o *InterceptorOptions) hasMethodRule(method string) bool {
+	for _, rule := range o.MethodRules {
+		if matchMethod(rule.Method, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// check 检查声明是否满足访问要求，未认证时始终失败
+func (r *MethodRule) check(claims *Claims) error {
+	if claims == nil {
+		return status.Error(codes.Unauthenticated, ErrMissingToken.Error())
+	}
+	if len(r.Roles) > 0 {
+		allowed := false
+		for _, role := range r.Roles {
+			if claims.HasRole(role) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return status.Error(codes.PermissionDenied, "forbidden")
+		}
+	}
+	for _, scope := range r.Scopes {
+		if !claims.HasScope(scope) {
+			return status.Error(codes.PermissionDenied, "insufficient scope")
+		}
+	}
+	return nil
+}
+
+// matchAnyMethod 检查方法是否匹配任意一个模式
+func matchAnyMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if matchMethod(pattern, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMethod 检查方法是否匹配模式，模式以 * 结尾时按前缀匹配
+func matchMethod(pattern, method string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(method, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == method
 }
 
 // wrappedServerStream 包装的服务器流
@@ -84,28 +214,48 @@ func (w *wrappedServerStream) Context() context.Context {
 	return w.ctx
 }
 
-// UnaryClientInterceptor 创建一元 RPC 客户端拦截器
+// UnaryClientInterceptor 创建使用固定令牌的一元 RPC 客户端拦截器
 func UnaryClientInterceptor(token string) grpc.UnaryClientInterceptor {
+	return UnaryClientInterceptorWithTokenSource(StaticTokenSource(token))
+}
+
+// StreamClientInterceptor 创建使用固定令牌的流式 RPC 客户端拦截器
+func StreamClientInterceptor(token string) grpc.StreamClientInterceptor {
+	return StreamClientInterceptorWithTokenSource(StaticTokenSource(token))
+}
+
+// UnaryClientInterceptorWithTokenSource 创建一元 RPC 客户端拦截器，每次调用从令牌来源获取令牌
+func UnaryClientInterceptorWithTokenSource(source TokenSource) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		// 添加令牌到元数据
-		newCtx := attachToken(ctx, token)
+		newCtx, err := attachToken(ctx, source)
+		if err != nil {
+			return err
+		}
 		return invoker(newCtx, method, req, reply, cc, opts...)
 	}
 }
 
-// StreamClientInterceptor 创建流式 RPC 客户端拦截器
-func StreamClientInterceptor(token string) grpc.StreamClientInterceptor {
+// StreamClientInterceptorWithTokenSource 创建流式 RPC 客户端拦截器，每次调用从令牌来源获取令牌
+func StreamClientInterceptorWithTokenSource(source TokenSource) grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		// 添加令牌到元数据
-		newCtx := attachToken(ctx, token)
+		newCtx, err := attachToken(ctx, source)
+		if err != nil {
+			return nil, err
+		}
 		return streamer(newCtx, desc, cc, method, opts...)
 	}
 }
 
-// attachToken 将令牌添加到上下文
-func attachToken(ctx context.Context, token string) context.Context {
+// attachToken 从令牌来源获取令牌并添加到上下文
+func attachToken(ctx context.Context, source TokenSource) (context.Context, error) {
+	token, err := source.Token(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
 	if token == "" {
-		return ctx
+		return ctx, nil
 	}
-	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
 }
@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/cache"
+)
+
+const (
+	// HeaderSignatureKey 签名密钥ID请求头
+	HeaderSignatureKey = "X-Signature-Key"
+	// HeaderSignatureTimestamp 签名时间戳请求头，Unix 秒
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	// HeaderSignatureNonce 签名随机数请求头
+	HeaderSignatureNonce = "X-Signature-Nonce"
+	// HeaderSignature 签名请求头，十六进制编码的 HMAC-SHA256
+	HeaderSignature = "X-Signature"
+)
+
+var (
+	// ErrInvalidSignature 无效的请求签名
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrReplayedRequest 重放的请求
+	ErrReplayedRequest = errors.New("request replayed")
+)
+
+// HMACKey HMAC签名密钥
+type HMACKey struct {
+	// Secret 签名密钥
+	Secret []byte
+	// Claims 密钥对应的声明
+	Claims *Claims
+}
+
+// HMACKeyLookup 根据密钥ID查找签名密钥，密钥不存在时返回 ErrKeyNotFound
+type HMACKeyLookup func(ctx context.Context, keyID string) (*HMACKey, error)
+
+// StaticHMACKeys 返回基于固定映射的签名密钥查找函数
+func StaticHMACKeys(keys map[string]*HMACKey) HMACKeyLookup {
+	return func(ctx context.Context, keyID string) (*HMACKey, error) {
+		key, ok := keys[keyID]
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+		return key, nil
+	}
+}
+
+// HMACOptions HMAC认证配置选项
+type HMACOptions struct {
+	MaxSkew    time.Duration // 允许的时钟偏差
+	NonceStore NonceStore    // 随机数存储，默认为内存存储，为 nil 时不做重放检查
+}
+
+// HMACOption 定义HMAC认证配置函数类型
+type HMACOption func(*HMACOptions)
+
+// WithMaxSkew 设置允许的时钟偏差
+func WithMaxSkew(d time.Duration) HMACOption {
+	return func(o *HMACOptions) {
+		o.MaxSkew = d
+	}
+}
+
+// WithNonceStore 设置随机数存储，store 为 nil 时关闭重放检查
+func WithNonceStore(store NonceStore) HMACOption {
+	return func(o *HMACOptions) {
+		o.NonceStore = store
+	}
+}
+
+// HMACAuthenticator HMAC请求签名认证器
+//
+// 签名内容为以换行分隔的请求方法、路径、时间戳、随机数和请求体的
+// SHA-256 摘要(十六进制)，使用 HMAC-SHA256 计算。时间戳超出允许偏差
+// 或随机数已被使用的请求会被拒绝。
+type HMACAuthenticator struct {
+	lookup HMACKeyLookup
+	opts   *HMACOptions
+}
+
+// NewHMACAuthenticator 创建HMAC请求签名认证器
+func NewHMACAuthenticator(lookup HMACKeyLookup, opts ...HMACOption) *HMACAuthenticator {
+	options := &HMACOptions{
+		MaxSkew:    time.Minute * 5,
+		NonceStore: NewMemoryNonceStore(),
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	return &HMACAuthenticator{
+		lookup: lookup,
+		opts:   options,
+	}
+}
+
+// Authenticate 验证请求签名
+func (a *HMACAuthenticator) Authenticate(ctx context.Context, req *Request) (*Claims, error) {
+	keyID := req.Header.Get(HeaderSignatureKey)
+	signature := req.Header.Get(HeaderSignature)
+	if keyID == "" && signature == "" {
+		return nil, ErrMissingToken
+	}
+
+	timestamp := req.Header.Get(HeaderSignatureTimestamp)
+	nonce := req.Header.Get(HeaderSignatureNonce)
+	if keyID == "" || signature == "" || timestamp == "" || nonce == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	// 检查时间戳
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	skew := time.Since(time.Unix(ts, 0))
+	if skew > a.opts.MaxSkew || skew < -a.opts.MaxSkew {
+		return nil, ErrInvalidSignature
+	}
+
+	key, err := a.lookup(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, ErrInvalidSignature
+		}
+		return nil, err
+	}
+
+	// 验证签名
+	body, err := req.Body()
+	if err != nil {
+		return nil, err
+	}
+	expected := computeSignature(key.Secret, req.Method, req.Path, timestamp, nonce, body)
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(actual, expected) {
+		return nil, ErrInvalidSignature
+	}
+
+	// 签名通过后再记录随机数，防止伪造请求占用随机数
+	if a.opts.NonceStore != nil {
+		ok, err := a.opts.NonceStore.Use(ctx, keyID+":"+nonce, a.opts.MaxSkew*2)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrReplayedRequest
+		}
+	}
+
+	if key.Claims == nil {
+		return &Claims{Subject: keyID}, nil
+	}
+	return cloneClaims(key.Claims), nil
+}
+
+// HMACSigner HMAC请求签名器，用于客户端签名请求
+type HMACSigner struct {
+	KeyID  string
+	Secret []byte
+}
+
+// Headers 计算请求签名并返回需要附加的请求头
+func (s *HMACSigner) Headers(method, path string, body []byte) (map[string]string, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := computeSignature(s.Secret, method, path, timestamp, nonce, body)
+
+	return map[string]string{
+		HeaderSignatureKey:       s.KeyID,
+		HeaderSignatureTimestamp: timestamp,
+		HeaderSignatureNonce:     nonce,
+		HeaderSignature:          hex.EncodeToString(signature),
+	}, nil
+}
+
+// SignRequest 为HTTP请求附加签名请求头
+func (s *HMACSigner) SignRequest(r *http.Request) error {
+	body, err := newHTTPRequest(nil, r, 0).Body()
+	if err != nil {
+		return err
+	}
+
+	headers, err := s.Headers(r.Method, r.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return nil
+}
+
+// computeSignature 计算请求签名
+func computeSignature(secret []byte, method, path, timestamp, nonce string, body []byte) []byte {
+	digest := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		method,
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(digest[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// newNonce 生成随机数
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NonceStore 随机数存储，用于防止请求重放
+type NonceStore interface {
+	// Use 记录随机数，随机数在 ttl 内已被使用时返回 false
+	Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// memoryNonceStore 内存随机数存储
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// NewMemoryNonceStore 创建内存随机数存储，仅在本实例内防止重放
+func NewMemoryNonceStore() NonceStore {
+	return &memoryNonceStore{
+		used: make(map[string]time.Time),
+	}
+}
+
+// Use 记录随机数
+func (s *memoryNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.used[nonce]; ok && expiresAt.After(now) {
+		return false, nil
+	}
+	for k, v := range s.used {
+		if !v.After(now) {
+			delete(s.used, k)
+		}
+	}
+	s.used[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// cacheNonceStore 基于 cache.Cache 的随机数存储
+type cacheNonceStore struct {
+	mu     sync.Mutex
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheNonceStore 创建基于缓存的随机数存储
+//
+// 使用 Redis 缓存时可在多个实例间共享。缓存实现 cache.NXCache 时检查与记录是原子的，
+// 否则仅在本实例内是原子的，多个实例同时收到同一请求时仍可能都通过检查。
+func NewCacheNonceStore(c cache.Cache, prefix string) NonceStore {
+	if prefix == "" {
+		prefix = "auth:nonce:"
+	}
+	return &cacheNonceStore{
+		cache:  c,
+		prefix: prefix,
+	}
+}
+
+// Use 记录随机数
+func (s *cacheNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	key := s.prefix + nonce
+	if nx, ok := s.cache.(cache.NXCache); ok {
+		return nx.SetNX(ctx, key, time.Now().Unix(), ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.cache.Get(ctx, key)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, cache.ErrKeyNotFound) && !errors.Is(err, cache.ErrKeyExpired) {
+		return false, err
+	}
+	if err := s.cache.Set(ctx, key, time.Now().Unix(), ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrSigningNotSupported 认证器不支持签发令牌
+var ErrSigningNotSupported = errors.New("token signing not supported")
+
+// maxJWKSSize JWKS文档的最大长度
+const maxJWKSSize = 1 << 20
+
+// JWKSOptions JWKS认证器配置选项
+type JWKSOptions struct {
+	HTTPClient         *http.Client  // HTTP客户端
+	RefreshInterval    time.Duration // 缓存刷新间隔
+	MinRefreshInterval time.Duration // 遇到未知 kid 时的最小刷新间隔
+	JWTOptions         []JWTOption   // 令牌验证选项
+}
+
+// JWKSOption 定义JWKS配置函数类型
+type JWKSOption func(*JWKSOptions)
+
+// WithHTTPClient 设置获取JWKS使用的HTTP客户端
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.HTTPClient = client
+	}
+}
+
+// WithRefreshInterval 设置缓存刷新间隔
+func WithRefreshInterval(interval time.Duration) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.RefreshInterval = interval
+	}
+}
+
+// WithMinRefreshInterval 设置遇到未知 kid 时的最小刷新间隔
+func WithMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.MinRefreshInterval = interval
+	}
+}
+
+// WithJWTOptions 设置令牌验证选项，如签发者、受众和时钟偏差
+func WithJWTOptions(opts ...JWTOption) JWKSOption {
+	return func(o *JWKSOptions) {
+		o.JWTOptions = append(o.JWTOptions, opts...)
+	}
+}
+
+// JWKSAuthenticator 基于远程JWKS文档的JWT认证器
+//
+// 认证器只负责验证令牌，密钥从远程URL获取并缓存。缓存过期或遇到未知 kid
+// 时会重新获取，未知 kid 触发的刷新受 MinRefreshInterval 限制。
+type JWKSAuthenticator struct {
+	url     string
+	opts    *JWKSOptions
+	jwtOpts *JWTOptions
+
+	mu        sync.RWMutex
+	keys      map[string]*Key
+	fetchedAt time.Time
+
+	fetchMu     sync.Mutex
+	attemptedAt time.Time
+}
+
+// NewJWKSAuthenticator 创建JWKS认证器
+func NewJWKSAuthenticator(url string, opts ...JWKSOption) *JWKSAuthenticator {
+	options := &JWKSOptions{
+		HTTPClient:         &http.Client{Timeout: time.Second * 5},
+		RefreshInterval:    time.Minute * 10,
+		MinRefreshInterval: time.Second * 30,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	return &JWKSAuthenticator{
+		url:     url,
+		opts:    options,
+		jwtOpts: newJWTOptions(options.JWTOptions...),
+		keys:    make(map[string]*Key),
+	}
+}
+
+// GenerateToken JWKS认证器不持有私钥，不支持签发令牌
+func (a *JWKSAuthenticator) GenerateToken(claims Claims) (string, error) {
+	return "", ErrSigningNotSupported
+}
+
+// ValidateToken 验证JWT令牌
+func (a *JWKSAuthenticator) ValidateToken(tokenString string) (*Claims, error) {
+	return a.ValidateTokenContext(context.Background(), tokenString)
+}
+
+// ValidateTokenContext 验证JWT令牌，遇到未知 kid 时使用 ctx 获取JWKS文档
+func (a *JWKSAuthenticator) ValidateTokenContext(ctx context.Context, tokenString string) (*Claims, error) {
+	return accessClaims(parseToken(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := a.getKey(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		return verificationKey(token, key)
+	}, a.jwtOpts))
+}
+
+// Refresh 立即从远程URL获取JWKS文档
+func (a *JWKSAuthenticator) Refresh(ctx context.Context) error {
+	a.fetchMu.Lock()
+	defer a.fetchMu.Unlock()
+
+	return a.fetch(ctx)
+}
+
+// getKey 获取指定 kid 的验证密钥
+func (a *JWKSAuthenticator) getKey(ctx context.Context, kid string) (*Key, error) {
+	a.mu.RLock()
+	key, ok := a.keys[kid]
+	fetchedAt := a.fetchedAt
+	a.mu.RUnlock()
+
+	if ok && time.Since(fetchedAt) < a.opts.RefreshInterval {
+		return key, nil
+	}
+
+	a.fetchMu.Lock()
+	defer a.fetchMu.Unlock()
+
+	// 等待锁期间可能已被其他请求刷新
+	a.mu.RLock()
+	key, ok = a.keys[kid]
+	fetchedAt = a.fetchedAt
+	a.mu.RUnlock()
+
+	stale := time.Since(fetchedAt) >= a.opts.RefreshInterval
+	if ok && !stale {
+		return key, nil
+	}
+	if time.Since(a.attemptedAt) < a.opts.MinRefreshInterval {
+		if ok {
+			return key, nil
+		}
+		return nil, ErrKeyNotFound
+	}
+
+	if err := a.fetch(ctx); err != nil {
+		// 获取失败时继续使用已缓存的密钥
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	a.mu.RLock()
+	key, ok = a.keys[kid]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// fetch 获取并解析JWKS文档，调用方需持有 fetchMu
+func (a *JWKSAuthenticator) fetch(ctx context.Context) error {
+	a.attemptedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth: fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.Key()
+		if err != nil {
+			// 跳过不支持的密钥类型
+			continue
+		}
+		keys[key.ID] = key
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	a.mu.Unlock()
+
+	return nil
+}
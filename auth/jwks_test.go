@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer 发布非对称认证器JWKS文档的测试服务
+type jwksServer struct {
+	*httptest.Server
+	auth    *AsymmetricAuthenticator
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, auth *AsymmetricAuthenticator) *jwksServer {
+	t.Helper()
+	s := &jwksServer{auth: auth}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		set, err := s.auth.JWKS()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestKey(t *testing.T, id string, alg string) *Key {
+	t.Helper()
+	var signer crypto.Signer
+	var err error
+	switch alg {
+	case "RS256":
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "EdDSA":
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := NewKey(id, signer)
+	if err != nil {
+		t.Fatalf("NewKey() error = %v", err)
+	}
+	if key.Algorithm != alg {
+		t.Fatalf("key algorithm = %s, want %s", key.Algorithm, alg)
+	}
+	return key
+}
+
+func newTestAsymmetric(t *testing.T, key *Key) *AsymmetricAuthenticator {
+	t.Helper()
+	a, err := NewAsymmetricAuthenticator(key, time.Hour, WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("NewAsymmetricAuthenticator() error = %v", err)
+	}
+	return a
+}
+
+func TestJWKSRoundTrip(t *testing.T) {
+	for _, alg := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(alg, func(t *testing.T) {
+			signer := newTestAsymmetric(t, newTestKey(t, "k1", alg))
+			server := newJWKSServer(t, signer)
+			verifier := NewJWKSAuthenticator(server.URL, WithJWTOptions(WithIssuer("issuer")))
+
+			token, err := signer.GenerateToken(Claims{UserID: "u1", Roles: []string{"admin"}})
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			claims, err := verifier.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if claims.UserID != "u1" || claims.Subject != "u1" || len(claims.Roles) != 1 {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestJWKSRejectsRefreshToken(t *testing.T) {
+	signer := newTestAsymmetric(t, newTestKey(t, "k1", "ES256"))
+	server := newJWKSServer(t, signer)
+	verifier := NewJWKSAuthenticator(server.URL, WithJWTOptions(WithIssuer("issuer")))
+
+	pair, err := NewTokenManager(signer).IssueTokenPair(context.Background(), Claims{UserID: "u1"})
+	if err != nil {
+		t.Fatalf("IssueTokenPair() error = %v", err)
+	}
+	if _, err := verifier.ValidateToken(pair.AccessToken); err != nil {
+		t.Errorf("ValidateToken(access) error = %v", err)
+	}
+	if _, err := verifier.ValidateToken(pair.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ValidateToken(refresh) error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	signer := newTestAsymmetric(t, newTestKey(t, "k1", "ES256"))
+	server := newJWKSServer(t, signer)
+	verifier := NewJWKSAuthenticator(server.URL, WithMinRefreshInterval(0))
+
+	old, _ := signer.GenerateToken(Claims{UserID: "u1"})
+	if _, err := verifier.ValidateToken(old); err != nil {
+		t.Fatalf("ValidateToken(k1) error = %v", err)
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Fatalf("fetches = %d, want 1", n)
+	}
+
+	// 缓存命中时不重新获取
+	if _, err := verifier.ValidateToken(old); err != nil {
+		t.Fatalf("ValidateToken(k1) error = %v", err)
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Fatalf("fetches = %d, want 1", n)
+	}
+
+	// 轮换后的 kid 不在缓存中，触发重新获取
+	if err := signer.Rotate(newTestKey(t, "k2", "ES256")); err != nil {
+		t.Fatal(err)
+	}
+	rotated, _ := signer.GenerateToken(Claims{UserID: "u1"})
+	if _, err := verifier.ValidateToken(rotated); err != nil {
+		t.Fatalf("ValidateToken(k2) error = %v", err)
+	}
+	if n := server.fetches.Load(); n != 2 {
+		t.Fatalf("fetches = %d, want 2", n)
+	}
+	if _, err := verifier.ValidateToken(old); err != nil {
+		t.Fatalf("ValidateToken(k1) after rotation error = %v", err)
+	}
+
+	// 旧密钥被移除后刷新，使用旧密钥签名的令牌失效
+	signer.RemoveKey("k1")
+	if err := verifier.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, err := verifier.ValidateToken(old); err == nil {
+		t.Fatal("ValidateToken(k1) after removal error = nil, want error")
+	}
+}
+
+func TestJWKSUnknownKid(t *testing.T) {
+	server := newJWKSServer(t, newTestAsymmetric(t, newTestKey(t, "k1", "RS256")))
+	verifier := NewJWKSAuthenticator(server.URL, WithMinRefreshInterval(time.Hour))
+
+	other := newTestAsymmetric(t, newTestKey(t, "unknown", "RS256"))
+	token, _ := other.GenerateToken(Claims{UserID: "u1"})
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.ValidateToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("ValidateToken() error = %v, want ErrInvalidToken", err)
+		}
+	}
+	// 未知 kid 触发的刷新受最小刷新间隔限制
+	if n := server.fetches.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+}
+
+func TestJWKSAlgorithmMismatch(t *testing.T) {
+	key := newTestKey(t, "k1", "RS256")
+	server := newJWKSServer(t, newTestAsymmetric(t, key))
+	verifier := NewJWKSAuthenticator(server.URL)
+
+	tests := map[string]func() (string, error){
+		// 使用公钥作为 HMAC 密钥的算法混淆攻击
+		"HS256": func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+			token.Header["kid"] = "k1"
+			pub, _ := json.Marshal(key.PublicKey)
+			return token.SignedString(pub)
+		},
+		"PS256": func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.MapClaims{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+			token.Header["kid"] = "k1"
+			return token.SignedString(key.PrivateKey)
+		},
+		"none": func() (string, error) {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+			token.Header["kid"] = "k1"
+			return token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		},
+	}
+	for name, sign := range tests {
+		token, err := sign()
+		if err != nil {
+			t.Fatalf("%s: sign error = %v", name, err)
+		}
+		if _, err := verifier.ValidateToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: ValidateToken() error = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestJWKSOptionalIssuedAt(t *testing.T) {
+	key := newTestKey(t, "k1", "ES256")
+	server := newJWKSServer(t, newTestAsymmetric(t, key))
+	verifier := NewJWKSAuthenticator(server.URL)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := verifier.ValidateToken(signed)
+	if err != nil {
+		t.Fatalf("ValidateToken() without iat error = %v", err)
+	}
+	if claims.Subject != "u1" || claims.IssuedAt != 0 {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	// exp 仍然是必需的
+	token = jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "u1"})
+	token.Header["kid"] = "k1"
+	signed, _ = token.SignedString(key.PrivateKey)
+	if _, err := verifier.ValidateToken(signed); !errors.Is(err, ErrInvalidClaims) {
+		t.Errorf("ValidateToken() without exp error = %v, want ErrInvalidClaims", err)
+	}
+}
+
+func TestJWKSRequestContext(t *testing.T) {
+	signer := newTestAsymmetric(t, newTestKey(t, "k1", "ES256"))
+	server := newJWKSServer(t, signer)
+	verifier := NewJWKSAuthenticator(server.URL)
+
+	token, _ := signer.GenerateToken(Claims{UserID: "u1"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := verifier.ValidateTokenContext(ctx, token); err == nil {
+		t.Fatal("ValidateTokenContext() with canceled context error = nil, want error")
+	}
+	if n := server.fetches.Load(); n != 0 {
+		t.Errorf("fetches = %d, want 0", n)
+	}
+}
+
+func TestJWKSResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys":[{"kty":"` + strings.Repeat("a", maxJWKSSize) + `"}]}`))
+	}))
+	defer server.Close()
+
+	verifier := NewJWKSAuthenticator(server.URL)
+	if err := verifier.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() error = nil, want error for oversized document")
+	}
+}
@@ -1,6 +1,11 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,47 +17,233 @@ type JWTAuthenticator struct {
 	secretKey []byte
 	// expiration 过期时间
 	expiration time.Duration
+	// opts 签发与验证选项
+	opts *JWTOptions
+}
+
+// JWTOptions JWT签发与验证选项
+type JWTOptions struct {
+	Issuer   string        // 签发者，签发时写入 iss，验证时要求一致
+	Audience string        // 受众，签发时作为默认 aud，验证时要求令牌包含该受众
+	Leeway   time.Duration // 验证 exp、nbf、iat 时允许的时钟偏差
+}
+
+// JWTOption 定义JWT配置函数类型
+type JWTOption func(*JWTOptions)
+
+// WithIssuer 设置签发者
+func WithIssuer(issuer string) JWTOption {
+	return func(o *JWTOptions) {
+		o.Issuer = issuer
+	}
+}
+
+// WithAudience 设置受众
+func WithAudience(audience string) JWTOption {
+	return func(o *JWTOptions) {
+		o.Audience = audience
+	}
+}
+
+// WithLeeway 设置允许的时钟偏差
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(o *JWTOptions) {
+		o.Leeway = leeway
+	}
+}
+
+// newJWTOptions 创建JWT选项
+func newJWTOptions(opts ...JWTOption) *JWTOptions {
+	options := &JWTOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+	return options
+}
+
+// parserOptions 返回对应的JWT解析选项
+func (o *JWTOptions) parserOptions() []jwt.ParserOption {
+	var opts []jwt.ParserOption
+	if o.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(o.Issuer))
+	}
+	if o.Audience != "" {
+		opts = append(opts, jwt.WithAudience(o.Audience))
+	}
+	if o.Leeway > 0 {
+		opts = append(opts, jwt.WithLeeway(o.Leeway))
+	}
+	return opts
 }
 
 // JWTClaims JWT声明
+//
+// Extra 中的自定义声明在编码时与其他声明平铺在同一层级，
+// 解码时所有未识别的声明都会放入 Extra。
 type JWTClaims struct {
 	jwt.RegisteredClaims
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID    string                 `json:"user_id"`
+	Username  string                 `json:"username"`
+	Role      string                 `json:"role"`
+	Roles     []string               `json:"roles,omitempty"`
+	Scope     string                 `json:"scope,omitempty"`
+	TenantID  string                 `json:"tenant_id,omitempty"`
+	TokenType string                 `json:"token_type,omitempty"`
+	FamilyID  string                 `json:"family_id,omitempty"`
+	Extra     map[string]interface{} `json:"-"`
+}
+
+// reservedClaims JWTClaims 已定义的声明名称
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"user_id": true, "username": true, "role": true, "roles": true, "scope": true,
+	"tenant_id": true, "token_type": true, "family_id": true,
+}
+
+// jwtClaimsAlias 避免 MarshalJSON 递归调用
+type jwtClaimsAlias JWTClaims
+
+// MarshalJSON 将自定义声明平铺编码
+func (c JWTClaims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(jwtClaimsAlias(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range c.Extra {
+		if !reservedClaims[k] {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON 解码声明并收集自定义声明
+func (c *JWTClaims) UnmarshalJSON(data []byte) error {
+	var alias jwtClaimsAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if reservedClaims[k] {
+			continue
+		}
+		if alias.Extra == nil {
+			alias.Extra = make(map[string]interface{})
+		}
+		alias.Extra[k] = v
+	}
+
+	*c = JWTClaims(alias)
+	return nil
 }
 
 // NewJWTAuthenticator 创建JWT认证器
-func NewJWTAuthenticator(secretKey string, expiration time.Duration) *JWTAuthenticator {
+func NewJWTAuthenticator(secretKey string, expiration time.Duration, opts ...JWTOption) *JWTAuthenticator {
 	return &JWTAuthenticator{
 		secretKey:  []byte(secretKey),
 		expiration: expiration,
+		opts:       newJWTOptions(opts...),
 	}
 }
 
 // GenerateToken 生成JWT令牌
 func (a *JWTAuthenticator) GenerateToken(claims Claims) (string, error) {
-	now := time.Now()
-	jwtClaims := JWTClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(a.expiration)),
-			IssuedAt:  jwt.NewNumericDate(now),
-		},
-		UserID:   claims.UserID,
-		Username: claims.Username,
-		Role:     claims.Role,
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newJWTClaims(claims, a.expiration, a.opts))
 	return token.SignedString(a.secretKey)
 }
 
-// ValidateToken 验证JWT令牌
+// ValidateToken 验证JWT令牌，刷新令牌返回 ErrInvalidToken
 func (a *JWTAuthenticator) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+	return accessClaims(a.parseToken(tokenString))
+}
+
+// parseToken 验证任意类型的JWT令牌
+func (a *JWTAuthenticator) parseToken(tokenString string) (*Claims, error) {
+	return parseToken(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return a.secretKey, nil
-	})
+	}, a.opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+}
+
+// newJWTClaims 根据声明创建JWT声明
+func newJWTClaims(claims Claims, expiration time.Duration, opts *JWTOptions) JWTClaims {
+	now := time.Now()
+	expiresAt := now.Add(expiration)
+	if claims.ExpiresAt > 0 {
+		expiresAt = time.Unix(claims.ExpiresAt, 0)
+	}
+	id := claims.ID
+	if id == "" {
+		id = newTokenID()
+	}
+	issuer := claims.Issuer
+	if issuer == "" {
+		issuer = opts.Issuer
+	}
+	audience := claims.Audience
+	if len(audience) == 0 && opts.Audience != "" {
+		audience = []string{opts.Audience}
+	}
+	subject := claims.Subject
+	if subject == "" {
+		subject = claims.UserID
+	}
+
+	registered := jwt.RegisteredClaims{
+		ID:        id,
+		Issuer:    issuer,
+		Subject:   subject,
+		Audience:  audience,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	if claims.NotBefore > 0 {
+		registered.NotBefore = jwt.NewNumericDate(time.Unix(claims.NotBefore, 0))
+	}
+
+	return JWTClaims{
+		RegisteredClaims: registered,
+		UserID:           claims.UserID,
+		Username:         claims.Username,
+		Role:             claims.Role,
+		Roles:            claims.Roles,
+		Scope:            strings.Join(claims.Scopes, " "),
+		TenantID:         claims.TenantID,
+		TokenType:        claims.TokenType,
+		FamilyID:         claims.FamilyID,
+		Extra:            claims.Extra,
+	}
+}
+
+// newTokenID 生成随机令牌ID
+func newTokenID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// parseToken 解析并验证JWT令牌
+func parseToken(tokenString string, keyFunc jwt.Keyfunc, opts *JWTOptions, parserOpts ...jwt.ParserOption) (*Claims, error) {
+	parserOpts = append(parserOpts, opts.parserOptions()...)
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, keyFunc, parserOpts...)
 	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		if errors.Is(err, jwt.ErrTokenInvalidIssuer) || errors.Is(err, jwt.ErrTokenInvalidAudience) {
+			return nil, ErrInvalidClaims
+		}
 		return nil, ErrInvalidToken
 	}
 
@@ -61,20 +252,32 @@ func (a *JWTAuthenticator) ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	jwtClaims, ok := token.Claims.(*JWTClaims)
-	if !ok {
+	if !ok || jwtClaims.ExpiresAt == nil {
 		return nil, ErrInvalidClaims
 	}
 
-	// 检查令牌是否过期
-	if jwtClaims.ExpiresAt.Unix() < time.Now().Unix() {
-		return nil, ErrExpiredToken
-	}
-
-	return &Claims{
+	claims := &Claims{
+		ID:        jwtClaims.ID,
+		TokenType: jwtClaims.TokenType,
+		FamilyID:  jwtClaims.FamilyID,
 		UserID:    jwtClaims.UserID,
 		Username:  jwtClaims.Username,
 		Role:      jwtClaims.Role,
+		Roles:     jwtClaims.Roles,
+		Scopes:    strings.Fields(jwtClaims.Scope),
+		TenantID:  jwtClaims.TenantID,
+		Issuer:    jwtClaims.Issuer,
+		Subject:   jwtClaims.Subject,
+		Audience:  jwtClaims.Audience,
 		ExpiresAt: jwtClaims.ExpiresAt.Unix(),
-		IssuedAt:  jwtClaims.IssuedAt.Unix(),
-	}, nil
+		Extra:     jwtClaims.Extra,
+	}
+	// iat 为可选声明，第三方签发的令牌可能不包含
+	if jwtClaims.IssuedAt != nil {
+		claims.IssuedAt = jwtClaims.IssuedAt.Unix()
+	}
+	if jwtClaims.NotBefore != nil {
+		claims.NotBefore = jwtClaims.NotBefore.Unix()
+	}
+	return claims, nil
 }
@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUnsupportedKey 不支持的密钥类型
+	ErrUnsupportedKey = errors.New("unsupported key")
+	// ErrKeyNotFound 密钥不存在
+	ErrKeyNotFound = errors.New("key not found")
+)
+
+// Key 非对称签名密钥
+type Key struct {
+	// ID 密钥ID，对应JWT头部的kid
+	ID string
+	// Algorithm 签名算法，如 RS256、ES256、EdDSA
+	Algorithm string
+	// PrivateKey 私钥，仅签名密钥需要
+	PrivateKey crypto.Signer
+	// PublicKey 公钥，用于验证签名
+	PublicKey crypto.PublicKey
+}
+
+// NewKey 根据私钥创建签名密钥，签名算法由密钥类型推断
+func NewKey(id string, privateKey crypto.Signer) (*Key, error) {
+	alg, err := algorithmOf(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		ID:         id,
+		Algorithm:  alg,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+	}, nil
+}
+
+// NewVerificationKey 根据公钥创建验证密钥
+func NewVerificationKey(id string, publicKey crypto.PublicKey) (*Key, error) {
+	alg, err := algorithmOf(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		ID:        id,
+		Algorithm: alg,
+		PublicKey: publicKey,
+	}, nil
+}
+
+// ParsePrivateKeyPEM 解析PEM格式的私钥(PKCS#8、PKCS#1 或 SEC1)
+func ParsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: invalid pem data", ErrUnsupportedKey)
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	return NewKey(id, signer)
+}
+
+// ParsePublicKeyPEM 解析PEM格式的公钥(PKIX 或证书)
+func ParsePublicKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: invalid pem data", ErrUnsupportedKey)
+	}
+
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return NewVerificationKey(id, cert.PublicKey)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerificationKey(id, key)
+}
+
+// signingMethod 返回密钥对应的JWT签名方法
+func (k *Key) signingMethod() (jwt.SigningMethod, error) {
+	method := jwt.GetSigningMethod(k.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("%w: algorithm %s", ErrUnsupportedKey, k.Algorithm)
+	}
+	return method, nil
+}
+
+// algorithmOf 根据公钥类型推断签名算法
+func algorithmOf(publicKey crypto.PublicKey) (string, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256.Alg(), nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256.Alg(), nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384.Alg(), nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512.Alg(), nil
+		}
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedKey, publicKey)
+}
+
+// JWK JSON Web Key(RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet JSON Web Key 集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK 将密钥的公钥部分转换为JWK
+func (k *Key) JWK() (JWK, error) {
+	jwk := JWK{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Algorithm,
+	}
+
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeBase64URL(pub.N.Bytes())
+		jwk.E = encodeBase64URL(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encodeBase64URL(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeBase64URL(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeBase64URL(pub)
+	default:
+		return JWK{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, k.PublicKey)
+	}
+
+	return jwk, nil
+}
+
+// Key 将JWK转换为验证密钥
+func (j JWK) Key() (*Key, error) {
+	var pub crypto.PublicKey
+
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(j.E)
+		if err != nil {
+			return nil, err
+		}
+		pub = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, j.Crv)
+		}
+		x, err := decodeBase64URL(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub = &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, j.Crv)
+		}
+		x, err := decodeBase64URL(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid ed25519 key size", ErrUnsupportedKey)
+		}
+		pub = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("%w: kty %s", ErrUnsupportedKey, j.Kty)
+	}
+
+	key, err := NewVerificationKey(j.Kid, pub)
+	if err != nil {
+		return nil, err
+	}
+	if j.Alg != "" {
+		key.Algorithm = j.Alg
+	}
+	return key, nil
+}
+
+// encodeBase64URL 无填充的 base64url 编码
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeBase64URL 无填充的 base64url 解码
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -1,44 +1,66 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// MiddlewareOptions 认证中间件配置选项
+type MiddlewareOptions struct {
+	MaxBodySize int64 // 认证器读取请求体时的上限，为 0 时不限制
+}
+
+// MiddlewareOption 定义认证中间件配置函数类型
+type MiddlewareOption func(*MiddlewareOptions)
+
+// WithMaxBodySize 设置认证器读取请求体时的上限，超出时返回 413
+func WithMaxBodySize(n int64) MiddlewareOption {
+	return func(o *MiddlewareOptions) {
+		o.MaxBodySize = n
+	}
+}
+
 // AuthMiddleware 认证中间件
-func AuthMiddleware(auth Authenticator) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 从请求头获取令牌
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": ErrMissingToken.Error(),
-			})
-			return
-		}
+func AuthMiddleware(auth Authenticator, opts ...MiddlewareOption) gin.HandlerFunc {
+	return RequestAuthMiddleware(BearerAuthenticator(auth), opts...)
+}
 
-		// 解析令牌
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": ErrInvalidToken.Error(),
-			})
-			return
-		}
+// RequestAuthMiddleware 基于请求认证器的认证中间件
+//
+// 可配合 Chain 同时支持 Bearer 令牌、API密钥和请求签名等多种认证方式。
+// 认证器读取的请求体默认最多 DefaultMaxBodySize 字节，超出时返回 413。
+func RequestAuthMiddleware(auth RequestAuthenticator, opts ...MiddlewareOption) gin.HandlerFunc {
+	options := &MiddlewareOptions{
+		MaxBodySize: DefaultMaxBodySize,
+	}
+	for _, o := range opts {
+		o(options)
+	}
 
-		// 验证令牌
-		claims, err := auth.ValidateToken(parts[1])
+	return func(c *gin.Context) {
+		// 认证请求
+		req := newHTTPRequest(c.Writer, c.Request, options.MaxBodySize)
+		claims, err := auth.Authenticate(c.Request.Context(), req)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			code := http.StatusUnauthorized
+			if errors.Is(err, ErrBodyTooLarge) {
+				code = http.StatusRequestEntityTooLarge
+			}
+			c.AbortWithStatusJSON(code, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
 
-		// 将声明保存到上下文
+		// 将声明保存到上下文，请求上下文同时保存原始令牌以便向下游传递
 		c.Set(string(ClaimsKey), claims)
+		ctx := NewContext(c.Request.Context(), claims)
+		if token, ok := requestToken(req); ok {
+			ctx = NewTokenContext(ctx, token)
+		}
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
@@ -55,7 +77,7 @@ func RequireRole(role string) gin.HandlerFunc {
 		}
 
 		userClaims, ok := claims.(*Claims)
-		if !ok || userClaims.Role != role {
+		if !ok || !userClaims.HasRole(role) {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"error": "forbidden",
 			})
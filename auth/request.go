@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/textproto"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxBodySize 认证时读取 HTTP 请求体的默认上限
+const DefaultMaxBodySize = 10 << 20
+
+// ErrBodyTooLarge 请求体超过上限
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// Request 认证请求，统一 HTTP 与 gRPC 请求的认证信息
+//
+// 对 gRPC 请求，Method 固定为 POST，Path 为完整方法名，Header 为入站元数据，
+// 一元调用的请求体为请求消息的确定性 protobuf 编码，流式调用没有请求体。
+type Request struct {
+	// Method 请求方法
+	Method string
+	// Path 请求路径，HTTP 请求包含查询参数
+	Path string
+	// Header 请求头
+	Header http.Header
+	// TLS 连接的TLS状态，非TLS连接为 nil
+	TLS *tls.ConnectionState
+
+	once sync.Once
+	load func() ([]byte, error)
+	body []byte
+	err  error
+}
+
+// NewHTTPRequest 从 HTTP 请求创建认证请求
+//
+// 请求体在首次调用 Body 时读取，读取后会恢复到原请求中供后续处理使用。
+// 请求体最多读取 DefaultMaxBodySize 字节，超出时 Body 返回 ErrBodyTooLarge。
+func NewHTTPRequest(r *http.Request) *Request {
+	return newHTTPRequest(nil, r, DefaultMaxBodySize)
+}
+
+// newHTTPRequest 从 HTTP 请求创建认证请求，maxBodySize 为 0 时不限制请求体大小
+//
+// 请求体超出上限时通过 w 通知服务器关闭连接，w 可以为 nil。
+func newHTTPRequest(w http.ResponseWriter, r *http.Request, maxBodySize int64) *Request {
+	return &Request{
+		Method: r.Method,
+		Path:   r.URL.RequestURI(),
+		Header: r.Header,
+		TLS:    r.TLS,
+		load: func() ([]byte, error) {
+			if r.Body == nil || r.Body == http.NoBody {
+				return nil, nil
+			}
+			reader := r.Body
+			if maxBodySize > 0 {
+				reader = http.MaxBytesReader(w, r.Body, maxBodySize)
+			}
+			body, err := io.ReadAll(reader)
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				return nil, ErrBodyTooLarge
+			}
+			return body, err
+		},
+	}
+}
+
+// newGRPCRequest 从 gRPC 调用创建认证请求
+func newGRPCRequest(ctx context.Context, method string, msg interface{}) *Request {
+	header := make(http.Header)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, v := range md {
+			header[textproto.CanonicalMIMEHeaderKey(k)] = v
+		}
+	}
+
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+
+	return &Request{
+		Method: http.MethodPost,
+		Path:   method,
+		Header: header,
+		TLS:    state,
+		load: func() ([]byte, error) {
+			m, ok := msg.(proto.Message)
+			if !ok {
+				return nil, nil
+			}
+			return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+		},
+	}
+}
+
+// Body 返回请求体
+func (r *Request) Body() ([]byte, error) {
+	r.once.Do(func() {
+		if r.load != nil {
+			r.body, r.err = r.load()
+		}
+	})
+	return r.body, r.err
+}
+
+// RequestAuthenticator 基于请求的认证器
+//
+// 请求中没有该认证器所需的凭证时应返回 ErrMissingToken，
+// 以便 Chain 继续尝试下一个认证器。
+type RequestAuthenticator interface {
+	// Authenticate 认证请求并返回声明
+	Authenticate(ctx context.Context, req *Request) (*Claims, error)
+}
+
+// RequestAuthenticatorFunc 函数形式的请求认证器
+type RequestAuthenticatorFunc func(ctx context.Context, req *Request) (*Claims, error)
+
+// Authenticate 实现 RequestAuthenticator 接口
+func (f RequestAuthenticatorFunc) Authenticate(ctx context.Context, req *Request) (*Claims, error) {
+	return f(ctx, req)
+}
+
+// BearerAuthenticator 将令牌认证器适配为请求认证器，从 Authorization 头读取 Bearer 令牌
+func BearerAuthenticator(auth Authenticator) RequestAuthenticator {
+	return RequestAuthenticatorFunc(func(ctx context.Context, req *Request) (*Claims, error) {
+		header := req.Header.Get("Authorization")
+		if header == "" {
+			return nil, ErrMissingToken
+		}
+		token, ok := parseBearer(header)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return validateToken(ctx, auth, token)
+	})
+}
+
+// Chain 按顺序尝试多个认证器
+//
+// 认证器返回 ErrMissingToken 时尝试下一个，返回其他错误时认证失败，
+// 所有认证器都缺少凭证时返回 ErrMissingToken。
+func Chain(auths ...RequestAuthenticator) RequestAuthenticator {
+	return RequestAuthenticatorFunc(func(ctx context.Context, req *Request) (*Claims, error) {
+		for _, a := range auths {
+			claims, err := a.Authenticate(ctx, req)
+			if errors.Is(err, ErrMissingToken) {
+				continue
+			}
+			return claims, err
+		}
+		return nil, ErrMissingToken
+	})
+}
+
+// requestToken 返回请求中的 Bearer 令牌
+func requestToken(req *Request) (string, bool) {
+	return parseBearer(req.Header.Get("Authorization"))
+}
+
+// cloneClaims 复制声明，避免请求间共享可变字段
+func cloneClaims(c *Claims) *Claims {
+	clone := *c
+	clone.Roles = append([]string(nil), c.Roles...)
+	clone.Scopes = append([]string(nil), c.Scopes...)
+	clone.Audience = append([]string(nil), c.Audience...)
+	if c.Extra != nil {
+		clone.Extra = make(map[string]interface{}, len(c.Extra))
+		for k, v := range c.Extra {
+			clone.Extra[k] = v
+		}
+	}
+	return &clone
+}
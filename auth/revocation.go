@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/cache"
+)
+
+// RevocationStore 令牌撤销列表
+//
+// 撤销记录以令牌ID(jti)为键，保留到令牌过期为止，过期后的令牌
+// 本身已无法通过验证，无需继续保存。
+type RevocationStore interface {
+	// Revoke 撤销令牌，记录保留到 expiresAt
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	// IsRevoked 检查令牌是否已被撤销
+	IsRevoked(ctx context.Context, id string) (bool, error)
+	// MarkUsed 原子地将一次性令牌标记为已使用，记录保留到 expiresAt，
+	// 返回令牌此前是否已被使用或撤销
+	MarkUsed(ctx context.Context, id string, expiresAt time.Time) (bool, error)
+}
+
+// memoryRevocationStore 内存撤销列表
+type memoryRevocationStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationStore 创建内存撤销列表
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke 撤销令牌
+func (s *memoryRevocationStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range s.revoked {
+		if v.Before(now) {
+			delete(s.revoked, k)
+		}
+	}
+	s.revoked[id] = expiresAt
+	return nil
+}
+
+// IsRevoked 检查令牌是否已被撤销
+func (s *memoryRevocationStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.revoked[id]
+	if !ok {
+		return false, nil
+	}
+	if expiresAt.Before(time.Now()) {
+		delete(s.revoked, id)
+		return false, nil
+	}
+	return true, nil
+}
+
+// MarkUsed 将令牌标记为已使用
+func (s *memoryRevocationStore) MarkUsed(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if v, ok := s.revoked[id]; ok && !v.Before(now) {
+		return true, nil
+	}
+	s.revoked[id] = expiresAt
+	return false, nil
+}
+
+// cacheRevocationStore 基于 cache.Cache 的撤销列表
+type cacheRevocationStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheRevocationStore 创建基于缓存的撤销列表
+//
+// 使用 Redis 缓存时撤销记录可在多个实例间共享。内存缓存的容量淘汰
+// 可能提前移除撤销记录，应配置足够的 MaxEntries。缓存实现 cache.NXCache 时
+// MarkUsed 是原子的，否则并发使用同一令牌时可能都被视为首次使用。
+func NewCacheRevocationStore(c cache.Cache, prefix string) RevocationStore {
+	if prefix == "" {
+		prefix = "auth:revoked:"
+	}
+	return &cacheRevocationStore{
+		cache:  c,
+		prefix: prefix,
+	}
+}
+
+// Revoke 撤销令牌
+func (s *cacheRevocationStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.cache.Set(ctx, s.prefix+id, expiresAt.Unix(), ttl)
+}
+
+// IsRevoked 检查令牌是否已被撤销
+func (s *cacheRevocationStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	_, err := s.cache.Get(ctx, s.prefix+id)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, cache.ErrKeyNotFound) || errors.Is(err, cache.ErrKeyExpired) {
+		return false, nil
+	}
+	return false, err
+}
+
+// MarkUsed 将令牌标记为已使用
+func (s *cacheRevocationStore) MarkUsed(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	if nx, ok := s.cache.(cache.NXCache); ok {
+		added, err := nx.SetNX(ctx, s.prefix+id, expiresAt.Unix(), ttl)
+		if err != nil {
+			return false, err
+		}
+		return !added, nil
+	}
+
+	used, err := s.IsRevoked(ctx, id)
+	if err != nil || used {
+		return used, err
+	}
+	return false, s.cache.Set(ctx, s.prefix+id, expiresAt.Unix(), ttl)
+}
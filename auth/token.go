@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// familyPrefix 令牌族撤销记录前缀
+const familyPrefix = "family:"
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	// AccessToken 访问令牌
+	AccessToken string `json:"access_token"`
+	// RefreshToken 刷新令牌
+	RefreshToken string `json:"refresh_token"`
+	// TokenType 令牌类型，固定为 Bearer
+	TokenType string `json:"token_type"`
+	// ExpiresAt 访问令牌过期时间
+	ExpiresAt int64 `json:"expires_at"`
+	// RefreshExpiresAt 刷新令牌过期时间
+	RefreshExpiresAt int64 `json:"refresh_expires_at"`
+}
+
+// TokenManagerOptions 令牌管理器配置选项
+type TokenManagerOptions struct {
+	RefreshExpiration time.Duration   // 刷新令牌有效期
+	Store             RevocationStore // 撤销列表
+}
+
+// TokenManagerOption 定义令牌管理器配置函数类型
+type TokenManagerOption func(*TokenManagerOptions)
+
+// WithRefreshExpiration 设置刷新令牌有效期
+func WithRefreshExpiration(expiration time.Duration) TokenManagerOption {
+	return func(o *TokenManagerOptions) {
+		o.RefreshExpiration = expiration
+	}
+}
+
+// WithRevocationStore 设置撤销列表
+func WithRevocationStore(store RevocationStore) TokenManagerOption {
+	return func(o *TokenManagerOptions) {
+		o.Store = store
+	}
+}
+
+// TokenManager 令牌管理器
+//
+// TokenManager 在 Authenticator 之上提供令牌对签发、刷新令牌轮换和令牌撤销。
+// 每次刷新都会撤销旧的刷新令牌，若已使用过的刷新令牌被再次提交，
+// 则视为令牌泄露并撤销整个令牌族。TokenManager 本身实现了 Authenticator，
+// 可直接传给 AuthMiddleware 和 gRPC 拦截器，被撤销的令牌将返回 ErrInvalidToken。
+type TokenManager struct {
+	auth Authenticator
+	opts *TokenManagerOptions
+}
+
+// NewTokenManager 创建令牌管理器
+func NewTokenManager(auth Authenticator, opts ...TokenManagerOption) *TokenManager {
+	options := &TokenManagerOptions{
+		RefreshExpiration: time.Hour * 24 * 7,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryRevocationStore()
+	}
+
+	return &TokenManager{
+		auth: auth,
+		opts: options,
+	}
+}
+
+// GenerateToken 生成访问令牌
+func (m *TokenManager) GenerateToken(claims Claims) (string, error) {
+	claims.TokenType = TokenTypeAccess
+	return m.auth.GenerateToken(claims)
+}
+
+// ValidateToken 验证访问令牌
+func (m *TokenManager) ValidateToken(token string) (*Claims, error) {
+	return m.ValidateTokenContext(context.Background(), token)
+}
+
+// ValidateTokenContext 验证访问令牌并检查撤销列表
+func (m *TokenManager) ValidateTokenContext(ctx context.Context, token string) (*Claims, error) {
+	claims, err := validateToken(ctx, m.auth, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType == TokenTypeRefresh {
+		return nil, ErrInvalidToken
+	}
+	if err := m.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// IssueTokenPair 签发访问令牌和刷新令牌
+func (m *TokenManager) IssueTokenPair(ctx context.Context, claims Claims) (*TokenPair, error) {
+	claims.FamilyID = newTokenID()
+	return m.issue(claims)
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧的刷新令牌随即失效
+func (m *TokenManager) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := m.parseToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh || claims.ID == "" || claims.FamilyID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	revoked, err := m.opts.Store.IsRevoked(ctx, familyPrefix+claims.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidToken
+	}
+
+	// 标记与检查在存储中原子完成，并发提交同一刷新令牌时只有一个请求成功。
+	// 已使用过的刷新令牌再次出现，撤销整个令牌族
+	used, err := m.opts.Store.MarkUsed(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		if err := m.RevokeFamily(ctx, claims.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrTokenReused
+	}
+
+	return m.issue(*claims)
+}
+
+// Revoke 撤销令牌，访问令牌和刷新令牌均可撤销
+func (m *TokenManager) Revoke(ctx context.Context, token string) error {
+	claims, err := m.parseToken(token)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return ErrInvalidClaims
+	}
+	return m.opts.Store.Revoke(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0))
+}
+
+// RevokeFamily 撤销令牌族中的所有令牌，通常用于登出所有会话
+func (m *TokenManager) RevokeFamily(ctx context.Context, familyID string) error {
+	return m.opts.Store.Revoke(ctx, familyPrefix+familyID, time.Now().Add(m.opts.RefreshExpiration))
+}
+
+// parseToken 验证访问令牌或刷新令牌
+func (m *TokenManager) parseToken(token string) (*Claims, error) {
+	if p, ok := m.auth.(tokenParser); ok {
+		return p.parseToken(token)
+	}
+	return m.auth.ValidateToken(token)
+}
+
+// issue 签发令牌对
+func (m *TokenManager) issue(claims Claims) (*TokenPair, error) {
+	access := claims
+	access.ID = ""
+	access.TokenType = TokenTypeAccess
+	access.ExpiresAt = 0
+	accessToken, err := m.auth.GenerateToken(access)
+	if err != nil {
+		return nil, err
+	}
+	accessClaims, err := m.auth.ValidateToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := claims
+	refresh.ID = ""
+	refresh.TokenType = TokenTypeRefresh
+	refresh.ExpiresAt = time.Now().Add(m.opts.RefreshExpiration).Unix()
+	refreshToken, err := m.auth.GenerateToken(refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        accessClaims.ExpiresAt,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// checkRevoked 检查令牌及其所属令牌族是否已被撤销
+func (m *TokenManager) checkRevoked(ctx context.Context, claims *Claims) error {
+	if claims.ID != "" {
+		revoked, err := m.opts.Store.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrInvalidToken
+		}
+	}
+	if claims.FamilyID != "" {
+		revoked, err := m.opts.Store.IsRevoked(ctx, familyPrefix+claims.FamilyID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrInvalidToken
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// TokenSource 令牌来源，每次调用时提供令牌
+//
+// 返回空字符串且没有错误时表示不附加令牌。
+type TokenSource interface {
+	// Token 返回当前可用的令牌
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc 函数形式的令牌来源
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token 实现 TokenSource 接口
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource 返回固定令牌的令牌来源
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// FetchFunc 获取令牌及其过期时间
+type FetchFunc func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+// refreshingTokenSource 缓存令牌并在过期前刷新的令牌来源
+type refreshingTokenSource struct {
+	fetch         FetchFunc
+	refreshBefore time.Duration
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewRefreshingTokenSource 创建自动刷新的令牌来源
+//
+// 令牌在过期前 refreshBefore 时间内会被重新获取，并发调用只会触发一次获取。
+func NewRefreshingTokenSource(fetch FetchFunc, refreshBefore time.Duration) TokenSource {
+	return &refreshingTokenSource{
+		fetch:         fetch,
+		refreshBefore: refreshBefore,
+	}
+}
+
+// Token 返回缓存的令牌，即将过期时重新获取
+func (s *refreshingTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(s.refreshBefore).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, expiresAt, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiresAt = expiresAt
+	return token, nil
+}
+
+// ClientCredentialsConfig OAuth2 客户端凭证模式配置
+type ClientCredentialsConfig struct {
+	TokenURL      string        // 令牌端点
+	ClientID      string        // 客户端ID
+	ClientSecret  string        // 客户端密钥
+	Scopes        []string      // 申请的授权范围
+	Audience      string        // 受众，部分授权服务器需要
+	HTTPClient    *http.Client  // HTTP客户端
+	RefreshBefore time.Duration // 提前刷新时间
+}
+
+// NewClientCredentialsTokenSource 创建基于 OAuth2 客户端凭证模式的令牌来源
+func NewClientCredentialsTokenSource(cfg ClientCredentialsConfig) TokenSource {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: time.Second * 10}
+	}
+	if cfg.RefreshBefore == 0 {
+		cfg.RefreshBefore = time.Second * 30
+	}
+	return NewRefreshingTokenSource(cfg.fetch, cfg.RefreshBefore)
+}
+
+// fetch 向令牌端点申请访问令牌
+func (cfg ClientCredentialsConfig) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	if cfg.Audience != "" {
+		form.Set("audience", cfg.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+
+	resp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("auth: fetch token: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, err
+	}
+	if body.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("auth: fetch token: %w", ErrMissingToken)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	if body.ExpiresIn > 0 {
+		expiresAt = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return body.AccessToken, expiresAt, nil
+}
+
+// PropagatingTokenSource 转发调用方令牌的令牌来源
+//
+// 依次从认证中间件或拦截器保存的上下文、gRPC 入站元数据中获取调用方的令牌，
+// 都不存在时使用 fallback，fallback 为 nil 时不附加令牌。
+func PropagatingTokenSource(fallback TokenSource) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		if token, ok := TokenFromContext(ctx); ok {
+			return token, nil
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if token, ok := parseBearer(firstValue(md.Get("authorization"))); ok {
+				return token, nil
+			}
+		}
+		if fallback != nil {
+			return fallback.Token(ctx)
+		}
+		return "", nil
+	})
+}
+
+// Transport 为出站HTTP请求附加令牌的 http.RoundTripper
+type Transport struct {
+	// Source 令牌来源
+	Source TokenSource
+	// Base 底层传输，为 nil 时使用 http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// NewTransport 创建附加令牌的HTTP传输
+func NewTransport(source TokenSource, base http.RoundTripper) *Transport {
+	return &Transport{
+		Source: source,
+		Base:   base,
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	if token == "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTripper 不应修改原请求
+	newReq := req.Clone(req.Context())
+	newReq.Header.Set("Authorization", "Bearer "+token)
+	return base.RoundTrip(newReq)
+}
+
+// parseBearer 解析 Bearer 认证头
+func parseBearer(header string) (string, bool) {
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// firstValue 返回第一个值
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
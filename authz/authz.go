@@ -0,0 +1,117 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/huangsc/blade/auth"
+	"github.com/huangsc/blade/logger"
+)
+
+var (
+	// ErrUnauthorized 缺少认证信息
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden 没有访问权限
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidPolicy 无效的策略
+	ErrInvalidPolicy = errors.New("invalid policy")
+)
+
+// Authorizer 授权器接口
+type Authorizer interface {
+	// Authorize 检查主体是否可以对资源执行操作，无权限时返回 ErrForbidden
+	Authorize(ctx context.Context, req *Request) error
+}
+
+// Request 授权请求
+type Request struct {
+	// Subject 访问主体
+	Subject *auth.Claims
+	// Resource 资源
+	Resource string
+	// Action 操作
+	Action string
+	// Attributes 请求属性，条件中通过 request.<key> 引用
+	Attributes map[string]interface{}
+}
+
+// Policy 授权策略
+type Policy struct {
+	// Roles 角色列表
+	Roles []Role `json:"roles" yaml:"roles"`
+	// Bindings 操作与权限的绑定，同一操作只能绑定一次
+	Bindings []Binding `json:"bindings" yaml:"bindings"`
+}
+
+// Role 角色
+type Role struct {
+	// Name 角色名称
+	Name string `json:"name" yaml:"name"`
+	// Inherits 继承的角色
+	Inherits []string `json:"inherits" yaml:"inherits"`
+	// Permissions 权限列表
+	Permissions []Permission `json:"permissions" yaml:"permissions"`
+}
+
+// Permission 权限
+type Permission struct {
+	// Resource 资源，支持 * 通配和 orders/* 前缀匹配
+	Resource string `json:"resource" yaml:"resource"`
+	// Action 操作，支持 * 通配
+	Action string `json:"action" yaml:"action"`
+	// Conditions 属性条件，全部满足时权限生效
+	Conditions []Condition `json:"conditions" yaml:"conditions"`
+}
+
+// Condition 属性条件
+//
+// Attribute 使用 subject.<field> 引用令牌声明(如 subject.tenant_id、subject.roles，
+// 自定义声明同样可用)，使用 request.<key> 引用请求属性，resource 和 action 引用
+// 当前资源和操作。Value 为字符串 ${...} 时表示引用另一个属性。
+// 属性不存在时除 exists 外的操作符均不满足，包括 ne 和 not_in。
+type Condition struct {
+	// Attribute 属性路径
+	Attribute string `json:"attribute" yaml:"attribute"`
+	// Operator 操作符: eq、ne、in、not_in、prefix、suffix、contains、exists
+	Operator string `json:"operator" yaml:"operator"`
+	// Value 比较值
+	Value interface{} `json:"value" yaml:"value"`
+}
+
+// Binding 操作绑定
+//
+// Operation 对 gRPC 为完整方法名(如 /user.v1.UserService/GetUser)，
+// 对 HTTP 为方法与路由模板(如 GET /v1/users/:id)。
+type Binding struct {
+	// Operation 操作名称
+	Operation string `json:"operation" yaml:"operation"`
+	// Resource 资源
+	Resource string `json:"resource" yaml:"resource"`
+	// Action 操作
+	Action string `json:"action" yaml:"action"`
+}
+
+// Options 授权配置选项
+type Options struct {
+	// DefaultDeny 未绑定的操作是否拒绝访问
+	DefaultDeny bool
+	// Logger 日志记录器，用于记录策略重新加载
+	Logger logger.Logger
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithDefaultDeny 设置未绑定的操作是否拒绝访问
+func WithDefaultDeny(deny bool) Option {
+	return func(o *Options) {
+		o.DefaultDeny = deny
+	}
+}
+
+// WithLogger 设置日志记录器
+func WithLogger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
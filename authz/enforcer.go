@@ -0,0 +1,343 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/huangsc/blade/auth"
+)
+
+// operators 支持的条件操作符
+var operators = map[string]bool{
+	"eq": true, "ne": true, "in": true, "not_in": true,
+	"prefix": true, "suffix": true, "contains": true, "exists": true,
+}
+
+// Enforcer 基于策略的授权器
+//
+// 策略在加载时展开角色继承关系，运行时通过原子替换支持热更新。
+type Enforcer struct {
+	policy atomic.Pointer[compiledPolicy]
+	opts   *Options
+}
+
+// compiledPolicy 展开继承关系后的策略
+type compiledPolicy struct {
+	permissions map[string][]Permission
+	bindings    map[string]Binding
+}
+
+// NewEnforcer 创建授权器
+func NewEnforcer(policy *Policy, opts ...Option) (*Enforcer, error) {
+	options := &Options{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	e := &Enforcer{opts: options}
+	if policy == nil {
+		policy = &Policy{}
+	}
+	if err := e.SetPolicy(policy); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetPolicy 校验并替换当前策略
+func (e *Enforcer) SetPolicy(policy *Policy) error {
+	compiled, err := compile(policy)
+	if err != nil {
+		return err
+	}
+	e.policy.Store(compiled)
+	return nil
+}
+
+// Binding 返回操作绑定的资源和操作
+func (e *Enforcer) Binding(operation string) (Binding, bool) {
+	b, ok := e.policy.Load().bindings[operation]
+	return b, ok
+}
+
+// Authorize 检查主体是否可以对资源执行操作
+func (e *Enforcer) Authorize(ctx context.Context, req *Request) error {
+	if req.Subject == nil {
+		return ErrUnauthorized
+	}
+
+	p := e.policy.Load()
+	roles := make([]string, 0, len(req.Subject.Roles)+1)
+	if req.Subject.Role != "" {
+		roles = append(roles, req.Subject.Role)
+	}
+	roles = append(roles, req.Subject.Roles...)
+
+	for _, role := range roles {
+		for _, perm := range p.permissions[role] {
+			if !matchResource(perm.Resource, req.Resource) || !matchAction(perm.Action, req.Action) {
+				continue
+			}
+			if evaluate(perm.Conditions, req) {
+				return nil
+			}
+		}
+	}
+	return ErrForbidden
+}
+
+// authorizeOperation 根据操作绑定进行授权
+func (e *Enforcer) authorizeOperation(ctx context.Context, operation string, claims *auth.Claims, attrs map[string]interface{}) error {
+	b, ok := e.Binding(operation)
+	if !ok {
+		if e.opts.DefaultDeny {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	return e.Authorize(ctx, &Request{
+		Subject:    claims,
+		Resource:   b.Resource,
+		Action:     b.Action,
+		Attributes: attrs,
+	})
+}
+
+// compile 校验策略并展开角色继承关系
+func compile(policy *Policy) (*compiledPolicy, error) {
+	roles := make(map[string]Role, len(policy.Roles))
+	for _, r := range policy.Roles {
+		if r.Name == "" {
+			return nil, fmt.Errorf("%w: role name is empty", ErrInvalidPolicy)
+		}
+		if _, ok := roles[r.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate role %s", ErrInvalidPolicy, r.Name)
+		}
+		for _, perm := range r.Permissions {
+			for _, c := range perm.Conditions {
+				if !operators[c.Operator] {
+					return nil, fmt.Errorf("%w: unknown operator %q in role %s", ErrInvalidPolicy, c.Operator, r.Name)
+				}
+			}
+		}
+		roles[r.Name] = r
+	}
+
+	p := &compiledPolicy{
+		permissions: make(map[string][]Permission, len(roles)),
+		bindings:    make(map[string]Binding, len(policy.Bindings)),
+	}
+
+	// 展开继承的权限
+	var expand func(name string, visiting map[string]bool) ([]Permission, error)
+	expand = func(name string, visiting map[string]bool) ([]Permission, error) {
+		if perms, ok := p.permissions[name]; ok {
+			return perms, nil
+		}
+		if visiting[name] {
+			return nil, fmt.Errorf("%w: inheritance cycle at role %s", ErrInvalidPolicy, name)
+		}
+		r, ok := roles[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown role %s", ErrInvalidPolicy, name)
+		}
+
+		visiting[name] = true
+		perms := append([]Permission(nil), r.Permissions...)
+		for _, parent := range r.Inherits {
+			inherited, err := expand(parent, visiting)
+			if err != nil {
+				return nil, err
+			}
+			perms = append(perms, inherited...)
+		}
+		delete(visiting, name)
+
+		p.permissions[name] = perms
+		return perms, nil
+	}
+	for name := range roles {
+		if _, err := expand(name, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, b := range policy.Bindings {
+		if b.Operation == "" {
+			return nil, fmt.Errorf("%w: binding operation is empty", ErrInvalidPolicy)
+		}
+		if _, ok := p.bindings[b.Operation]; ok {
+			return nil, fmt.Errorf("%w: duplicate binding for operation %s", ErrInvalidPolicy, b.Operation)
+		}
+		p.bindings[b.Operation] = b
+	}
+
+	return p, nil
+}
+
+// matchResource 匹配资源，支持 * 和前缀通配
+func matchResource(pattern, resource string) bool {
+	if pattern == "*" || pattern == resource {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(resource, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+// matchAction 匹配操作，支持 * 通配
+func matchAction(pattern, action string) bool {
+	return pattern == "*" || pattern == action
+}
+
+// evaluate 检查条件是否全部满足
+func evaluate(conditions []Condition, req *Request) bool {
+	for _, c := range conditions {
+		if !evaluateCondition(c, req) {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateCondition 检查单个条件
+func evaluateCondition(c Condition, req *Request) bool {
+	actual, exists := attribute(req, c.Attribute)
+	expected := c.Value
+	if ref, ok := reference(expected); ok {
+		v, found := attribute(req, ref)
+		if !found {
+			return false
+		}
+		expected = v
+	}
+
+	if c.Operator == "exists" {
+		if b, ok := expected.(bool); ok && !b {
+			return !exists
+		}
+		return exists
+	}
+
+	// 属性不存在时除 exists 外的操作符均不满足，避免缺少属性的请求通过 ne、not_in 条件
+	if !exists {
+		return false
+	}
+
+	switch c.Operator {
+	case "eq":
+		return equal(actual, expected)
+	case "ne":
+		return !equal(actual, expected)
+	case "in":
+		return contains(toSlice(expected), actual)
+	case "not_in":
+		return !contains(toSlice(expected), actual)
+	case "prefix":
+		return strings.HasPrefix(toString(actual), toString(expected))
+	case "suffix":
+		return strings.HasSuffix(toString(actual), toString(expected))
+	case "contains":
+		if values := toSlice(actual); values != nil {
+			return contains(values, expected)
+		}
+		return strings.Contains(toString(actual), toString(expected))
+	}
+	return false
+}
+
+// reference 解析 ${...} 形式的属性引用
+func reference(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "${") || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+	return s[2 : len(s)-1], true
+}
+
+// attribute 获取属性值
+func attribute(req *Request, path string) (interface{}, bool) {
+	switch {
+	case path == "resource":
+		return req.Resource, true
+	case path == "action":
+		return req.Action, true
+	case strings.HasPrefix(path, "subject."):
+		return subjectAttribute(req.Subject, strings.TrimPrefix(path, "subject."))
+	case strings.HasPrefix(path, "request."):
+		v, ok := req.Attributes[strings.TrimPrefix(path, "request.")]
+		return v, ok
+	}
+	return nil, false
+}
+
+// subjectAttribute 获取主体属性
+func subjectAttribute(claims *auth.Claims, name string) (interface{}, bool) {
+	if claims == nil {
+		return nil, false
+	}
+
+	switch name {
+	case "user_id":
+		return claims.UserID, claims.UserID != ""
+	case "username":
+		return claims.Username, claims.Username != ""
+	case "role":
+		return claims.Role, claims.Role != ""
+	case "roles":
+		return claims.Roles, len(claims.Roles) > 0
+	case "scopes":
+		return claims.Scopes, len(claims.Scopes) > 0
+	case "tenant_id":
+		return claims.TenantID, claims.TenantID != ""
+	case "issuer":
+		return claims.Issuer, claims.Issuer != ""
+	case "subject":
+		return claims.Subject, claims.Subject != ""
+	case "audience":
+		return claims.Audience, len(claims.Audience) > 0
+	}
+	return claims.Get(name)
+}
+
+// equal 比较两个值的字符串形式
+func equal(a, b interface{}) bool {
+	return toString(a) == toString(b)
+}
+
+// contains 检查切片中是否包含指定值
+func contains(values []interface{}, v interface{}) bool {
+	for _, item := range values {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// toString 将值转换为字符串
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// toSlice 将值转换为切片，非切片值返回 nil
+func toSlice(v interface{}) []interface{} {
+	switch vs := v.(type) {
+	case []interface{}:
+		return vs
+	case []string:
+		out := make([]interface{}, len(vs))
+		for i, s := range vs {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
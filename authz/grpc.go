@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/huangsc/blade/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Attributer 可提供授权属性的请求消息
+type Attributer interface {
+	// AuthzAttributes 返回授权属性
+	AuthzAttributes() map[string]interface{}
+}
+
+// UnaryServerInterceptor 创建一元 RPC 授权拦截器
+//
+// 操作名称为完整方法名，请求消息实现 Attributer 时使用其返回的属性，
+// 否则使用 protobuf 消息的顶层标量字段作为请求属性。
+func UnaryServerInterceptor(e *Enforcer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		claims, _ := auth.FromContext(ctx)
+		if err := e.authorizeOperation(ctx, info.FullMethod, claims, messageAttributes(req)); err != nil {
+			return nil, statusError(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 创建流式 RPC 授权拦截器
+func StreamServerInterceptor(e *Enforcer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		claims, _ := auth.FromContext(ctx)
+		if err := e.authorizeOperation(ctx, info.FullMethod, claims, nil); err != nil {
+			return statusError(err)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// messageAttributes 从请求消息中提取属性
+func messageAttributes(req interface{}) map[string]interface{} {
+	if a, ok := req.(Attributer); ok {
+		return a.AuthzAttributes()
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	attrs := make(map[string]interface{})
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsList() || fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			return true
+		}
+		attrs[string(fd.Name())] = v.Interface()
+		return true
+	})
+	return attrs
+}
+
+// statusError 将授权错误转换为 gRPC 状态错误
+func statusError(err error) error {
+	if errors.Is(err, ErrUnauthorized) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return status.Error(codes.PermissionDenied, ErrForbidden.Error())
+}
@@ -0,0 +1,160 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/huangsc/blade/config"
+	"github.com/huangsc/blade/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile 从文件加载策略，根据扩展名选择 JSON 或 YAML 格式
+func LoadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, policy)
+	default:
+		err = json.Unmarshal(data, policy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return policy, nil
+}
+
+// LoadConfig 从配置中心加载策略
+func LoadConfig(c config.Config, key string) (*Policy, error) {
+	value, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	if err := value.Scan(policy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return policy, nil
+}
+
+// WatchFile 加载策略文件并按间隔检查文件修改时间，修改时间变化时重新加载
+//
+// 修改时间早于原文件时同样重新加载，例如原子替换或保留时间戳复制的文件。
+// 首次加载失败时返回错误，之后的重新加载失败会保留原策略并记录日志。
+// 监听在 ctx 取消后停止。
+func (e *Enforcer) WatchFile(ctx context.Context, path string, interval time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := e.loadFile(path); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		modTime := info.ModTime()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(path)
+				if err != nil || info.ModTime().Equal(modTime) {
+					continue
+				}
+				modTime = info.ModTime()
+				if err := e.loadFile(path); err != nil {
+					e.logError("authz: reload policy file failed", err, logger.String("path", path))
+					continue
+				}
+				e.logInfo("authz: policy file reloaded", logger.String("path", path))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// WatchConfig 从配置中心加载策略并监听配置变更
+func (e *Enforcer) WatchConfig(ctx context.Context, c config.Config, key string) error {
+	policy, err := LoadConfig(c, key)
+	if err != nil {
+		return err
+	}
+	if err := e.SetPolicy(policy); err != nil {
+		return err
+	}
+
+	w, err := c.Watch(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer w.Stop()
+		for {
+			change, err := w.Next()
+			if err != nil {
+				return
+			}
+			if change.Type == config.Delete || change.Value == nil {
+				continue
+			}
+
+			policy := &Policy{}
+			if err := change.Value.Scan(policy); err != nil {
+				e.logError("authz: decode policy failed", err, logger.String("key", key))
+				continue
+			}
+			if err := e.SetPolicy(policy); err != nil {
+				e.logError("authz: reload policy failed", err, logger.String("key", key))
+				continue
+			}
+			e.logInfo("authz: policy reloaded", logger.String("key", key))
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		w.Stop()
+	}()
+
+	return nil
+}
+
+// loadFile 加载策略文件并替换当前策略
+func (e *Enforcer) loadFile(path string) error {
+	policy, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	return e.SetPolicy(policy)
+}
+
+// logInfo 输出信息日志
+func (e *Enforcer) logInfo(msg string, fields ...logger.Field) {
+	if e.opts.Logger != nil {
+		e.opts.Logger.Info(msg, fields...)
+	}
+}
+
+// logError 输出错误日志
+func (e *Enforcer) logError(msg string, err error, fields ...logger.Field) {
+	if e.opts.Logger != nil {
+		e.opts.Logger.Error(msg, append(fields, logger.Error(err))...)
+	}
+}
@@ -0,0 +1,85 @@
+package authz
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/auth"
+)
+
+// Middleware 根据策略绑定对HTTP请求授权
+//
+// 操作名称为请求方法与路由模板，例如 GET /v1/users/:id。
+// 请求方法与路径可在条件中通过 request.method、request.path 引用，
+// 路径参数通过 request.param.<name> 引用，查询参数通过 request.query.<name> 引用。
+func Middleware(e *Enforcer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := c.Request.Method + " " + c.FullPath()
+		if err := e.authorizeOperation(c.Request.Context(), operation, ginClaims(c), ginAttributes(c)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Require 要求对指定资源拥有操作权限
+func Require(a Authorizer, resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := a.Authorize(c.Request.Context(), &Request{
+			Subject:    ginClaims(c),
+			Resource:   resource,
+			Action:     action,
+			Attributes: ginAttributes(c),
+		})
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ginClaims 获取认证中间件保存的声明
+func ginClaims(c *gin.Context) *auth.Claims {
+	if v, ok := c.Get(string(auth.ClaimsKey)); ok {
+		if claims, ok := v.(*auth.Claims); ok {
+			return claims
+		}
+	}
+	claims, _ := auth.FromContext(c.Request.Context())
+	return claims
+}
+
+// ginAttributes 收集请求属性
+//
+// 查询参数与路径参数分别以 query. 和 param. 为前缀，不会覆盖 method 和 path。
+func ginAttributes(c *gin.Context) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+	}
+	for k, v := range c.Request.URL.Query() {
+		if len(v) > 0 {
+			attrs["query."+k] = v[0]
+		}
+	}
+	for _, p := range c.Params {
+		attrs["param."+p.Key] = p.Value
+	}
+	return attrs
+}
+
+// abortWithError 根据授权错误返回响应
+func abortWithError(c *gin.Context, err error) {
+	if errors.Is(err, ErrUnauthorized) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error": ErrForbidden.Error(),
+	})
+}
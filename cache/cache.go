@@ -65,6 +65,14 @@ type Cache interface {
 	Close() error
 }
 
+// NXCache 支持原子写入的缓存
+//
+// 用于多个实例间需要互斥的场景，如记录一次性令牌是否已被使用。
+type NXCache interface {
+	// SetNX 键不存在或已过期时设置缓存值，返回是否写入
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+}
+
 // Option 配置选项函数
 type Option func(*Options)
 
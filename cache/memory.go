@@ -86,6 +86,34 @@ func (m *memory) Set(ctx context.Context, key string, value interface{}, ttl tim
 	return nil
 }
 
+// SetNX 键不存在或已过期时设置缓存值
+func (m *memory) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = m.options.TTL
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if item, ok := m.items[key]; ok && !item.ExpireAt.Before(time.Now()) {
+		return false, nil
+	}
+
+	// 检查是否超过最大条目数
+	if m.options.MaxEntries > 0 && len(m.items) >= m.options.MaxEntries {
+		m.evict()
+	}
+
+	m.items[key] = &Item{
+		Key:       key,
+		Value:     value,
+		ExpireAt:  time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+
+	return true, nil
+}
+
 // Delete 删除缓存值
 func (m *memory) Delete(ctx context.Context, key string) error {
 	m.mutex.Lock()
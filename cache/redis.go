@@ -103,6 +103,28 @@ func (r *redisCache) Set(ctx context.Context, key string, value interface{}, ttl
 	return r.client.Set(ctx, key, data, ttl).Err()
 }
 
+// SetNX 键不存在时设置缓存值，使用 Redis SET NX 保证多个实例间的原子性
+func (r *redisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = r.options.TTL
+	}
+
+	item := Item{
+		Key:       key,
+		Value:     value,
+		ExpireAt:  time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
+
+	key = r.options.KeyPrefix + key
+	return r.client.SetNX(ctx, key, data, ttl).Result()
+}
+
 // Delete 删除缓存值
 func (r *redisCache) Delete(ctx context.Context, key string) error {
 	if r.options.OnEvicted != nil {
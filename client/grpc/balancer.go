@@ -0,0 +1,245 @@
+package grpc
+
+import (
+	"context"
+	"hash/crc32"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/balancer/roundrobin"
+)
+
+const (
+	// BalancerRoundRobin 轮询
+	BalancerRoundRobin = roundrobin.Name
+	// BalancerWeighted 按服务实例元数据中的 WeightKey 加权轮询
+	BalancerWeighted = "blade_weighted"
+	// BalancerP2C 随机选择两个连接，使用延迟与并发数较低的一个
+	BalancerP2C = "blade_p2c"
+	// BalancerHash 按 WithHashKey 设置的请求键一致性哈希，未设置键时随机选择
+	BalancerHash = "blade_hash"
+
+	// hashReplicas 一致性哈希中每个连接的虚拟节点数
+	hashReplicas = 160
+	// p2cDecay 延迟指数移动平均的衰减时间
+	p2cDecay = time.Second * 10
+	// p2cForcePick 连接超过该时间未被选择时强制选择一次，以更新其延迟统计
+	p2cForcePick = time.Second * 3
+)
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(BalancerWeighted, &weightedPickerBuilder{}, base.Config{HealthCheck: true}))
+	balancer.Register(base.NewBalancerBuilder(BalancerP2C, &p2cPickerBuilder{}, base.Config{HealthCheck: true}))
+	balancer.Register(base.NewBalancerBuilder(BalancerHash, &hashPickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+// hashKey 上下文中一致性哈希请求键的键
+type hashKey struct{}
+
+// WithHashKey 设置一致性哈希的请求键，使用 BalancerHash 时相同键的请求发往同一连接
+func WithHashKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, hashKey{}, key)
+}
+
+// hashKeyFromContext 获取一致性哈希的请求键
+func hashKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(hashKey{}).(string)
+	return key, ok
+}
+
+// weightedPickerBuilder 加权轮询选择器构建器
+type weightedPickerBuilder struct{}
+
+// Build 实现 base.PickerBuilder 接口
+func (b *weightedPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &weightedPicker{}
+	for sc, sci := range info.ReadySCs {
+		p.nodes = append(p.nodes, &weightedNode{subConn: sc, weight: addressWeight(sci.Address)})
+	}
+	return p
+}
+
+// weightedPicker 平滑加权轮询选择器
+type weightedPicker struct {
+	mu    sync.Mutex
+	nodes []*weightedNode
+}
+
+// weightedNode 加权轮询的连接
+type weightedNode struct {
+	subConn balancer.SubConn
+	weight  int
+	current int
+}
+
+// Pick 实现 balancer.Picker 接口
+func (p *weightedPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		total int
+		best  *weightedNode
+	)
+	for _, node := range p.nodes {
+		node.current += node.weight
+		total += node.weight
+		if best == nil || node.current > best.current {
+			best = node
+		}
+	}
+	best.current -= total
+	return balancer.PickResult{SubConn: best.subConn}, nil
+}
+
+// p2cPickerBuilder P2C 选择器构建器
+type p2cPickerBuilder struct{}
+
+// Build 实现 base.PickerBuilder 接口
+//
+// 连接的延迟统计保存在选择器中，连接状态变化重建选择器后重新统计。
+func (b *p2cPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &p2cPicker{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	now := time.Now().UnixNano()
+	for sc := range info.ReadySCs {
+		node := &p2cNode{subConn: sc}
+		node.picked.Store(now)
+		p.nodes = append(p.nodes, node)
+	}
+	return p
+}
+
+// p2cPicker P2C 最低延迟选择器
+type p2cPicker struct {
+	mu    sync.Mutex
+	rand  *rand.Rand
+	nodes []*p2cNode
+}
+
+// p2cNode P2C 的连接及其延迟统计
+type p2cNode struct {
+	subConn  balancer.SubConn
+	inflight atomic.Int64
+	picked   atomic.Int64
+	mu       sync.Mutex
+	latency  float64
+	updated  time.Time
+}
+
+// Pick 实现 balancer.Picker 接口
+func (p *p2cPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	node := p.nodes[0]
+	if len(p.nodes) > 1 {
+		p.mu.Lock()
+		a := p.rand.Intn(len(p.nodes))
+		b := p.rand.Intn(len(p.nodes) - 1)
+		p.mu.Unlock()
+		if b >= a {
+			b++
+		}
+		var other *p2cNode
+		node, other = p.nodes[a], p.nodes[b]
+		if other.load() < node.load() {
+			node, other = other, node
+		}
+		if time.Since(time.Unix(0, other.picked.Load())) > p2cForcePick {
+			node = other
+		}
+	}
+
+	node.inflight.Add(1)
+	start := time.Now()
+	node.picked.Store(start.UnixNano())
+	return balancer.PickResult{
+		SubConn: node.subConn,
+		Done: func(balancer.DoneInfo) {
+			node.inflight.Add(-1)
+			node.observe(time.Since(start))
+		},
+	}, nil
+}
+
+// load 返回连接负载，为延迟移动平均与并发数的乘积
+func (n *p2cNode) load() float64 {
+	n.mu.Lock()
+	latency := n.latency
+	n.mu.Unlock()
+	return (latency + 1) * float64(n.inflight.Load()+1)
+}
+
+// observe 记录请求延迟，按距上次记录的时间衰减历史值
+func (n *p2cNode) observe(rtt time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	if n.updated.IsZero() {
+		n.latency = float64(rtt)
+	} else {
+		w := float64(now.Sub(n.updated)) / float64(p2cDecay)
+		if w > 1 {
+			w = 1
+		}
+		n.latency = n.latency*(1-w) + float64(rtt)*w
+	}
+	n.updated = now
+}
+
+// hashPickerBuilder 一致性哈希选择器构建器
+type hashPickerBuilder struct{}
+
+// Build 实现 base.PickerBuilder 接口
+func (b *hashPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &hashPicker{
+		ring:     make([]uint32, 0, len(info.ReadySCs)*hashReplicas),
+		subConns: make(map[uint32]balancer.SubConn, len(info.ReadySCs)*hashReplicas),
+	}
+	for sc, sci := range info.ReadySCs {
+		for i := 0; i < hashReplicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(sci.Address.Addr + "#" + strconv.Itoa(i)))
+			if _, ok := p.subConns[h]; ok {
+				continue
+			}
+			p.ring = append(p.ring, h)
+			p.subConns[h] = sc
+		}
+	}
+	sort.Slice(p.ring, func(i, j int) bool { return p.ring[i] < p.ring[j] })
+	return p
+}
+
+// hashPicker 一致性哈希选择器
+type hashPicker struct {
+	ring     []uint32
+	subConns map[uint32]balancer.SubConn
+}
+
+// Pick 实现 balancer.Picker 接口
+func (p *hashPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	var h uint32
+	if key, ok := hashKeyFromContext(info.Ctx); ok {
+		h = crc32.ChecksumIEEE([]byte(key))
+	} else {
+		h = rand.Uint32()
+	}
+	i := sort.Search(len(p.ring), func(i int) bool { return p.ring[i] >= h })
+	if i == len(p.ring) {
+		i = 0
+	}
+	return balancer.PickResult{SubConn: p.subConns[p.ring[i]]}, nil
+}
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+// fakeSubConn 测试用连接
+type fakeSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+// buildInfo 创建包含指定地址与权重的选择器构建信息
+func buildInfo(weights map[string]int) base.PickerBuildInfo {
+	info := base.PickerBuildInfo{ReadySCs: make(map[balancer.SubConn]base.SubConnInfo)}
+	for addr, weight := range weights {
+		info.ReadySCs[&fakeSubConn{addr: addr}] = base.SubConnInfo{
+			Address: resolver.Address{Addr: addr, Attributes: attributes.New(weightKey{}, weight)},
+		}
+	}
+	return info
+}
+
+// pick 选择一次连接并返回地址
+func pick(t *testing.T, p balancer.Picker, ctx context.Context) (string, balancer.PickResult) {
+	t.Helper()
+	res, err := p.Pick(balancer.PickInfo{FullMethodName: "/test.Service/Method", Ctx: ctx})
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+	return res.SubConn.(*fakeSubConn).addr, res
+}
+
+func TestWeightedPicker(t *testing.T) {
+	p := (&weightedPickerBuilder{}).Build(buildInfo(map[string]int{"a": 100, "b": 200, "c": 300}))
+
+	counts := make(map[string]int)
+	for i := 0; i < 600; i++ {
+		addr, _ := pick(t, p, context.Background())
+		counts[addr]++
+	}
+	want := map[string]int{"a": 100, "b": 200, "c": 300}
+	for addr, n := range want {
+		if counts[addr] != n {
+			t.Errorf("picks of %s = %d, want %d (all: %v)", addr, counts[addr], n, counts)
+		}
+	}
+}
+
+func TestP2CPickerSpreadsLoad(t *testing.T) {
+	p := (&p2cPickerBuilder{}).Build(buildInfo(map[string]int{"a": 1, "b": 1, "c": 1, "d": 1}))
+
+	counts := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		// 请求未完成时并发数增加，后续请求应分散到其他连接
+		addr, _ := pick(t, p, context.Background())
+		counts[addr]++
+	}
+	if len(counts) != 4 {
+		t.Fatalf("picked subconns = %v, want all 4", counts)
+	}
+	for addr, n := range counts {
+		if n < 50 {
+			t.Errorf("picks of %s = %d, want at least 50 (all: %v)", addr, n, counts)
+		}
+	}
+}
+
+func TestP2CPickerPrefersLowLatency(t *testing.T) {
+	p := (&p2cPickerBuilder{}).Build(buildInfo(map[string]int{"fast": 1, "slow": 1})).(*p2cPicker)
+	for _, node := range p.nodes {
+		if node.subConn.(*fakeSubConn).addr == "slow" {
+			node.latency = 1e9
+		} else {
+			node.latency = 1e6
+		}
+		node.updated = node.updated.Add(1)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		addr, res := pick(t, p, context.Background())
+		res.Done(balancer.DoneInfo{})
+		counts[addr]++
+	}
+	if counts["fast"] <= counts["slow"] {
+		t.Errorf("picks = %v, want fast subconn preferred", counts)
+	}
+}
+
+func TestHashPicker(t *testing.T) {
+	p := (&hashPickerBuilder{}).Build(buildInfo(map[string]int{"a": 1, "b": 1, "c": 1}))
+
+	used := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		ctx := WithHashKey(context.Background(), fmt.Sprintf("user-%d", i))
+		first, _ := pick(t, p, ctx)
+		for j := 0; j < 10; j++ {
+			if addr, _ := pick(t, p, ctx); addr != first {
+				t.Fatalf("key user-%d picked %s then %s", i, first, addr)
+			}
+		}
+		used[first] = true
+	}
+	if len(used) < 2 {
+		t.Errorf("50 keys mapped to %d subconns, want keys spread across subconns", len(used))
+	}
+}
+
+func TestPickerBuildersWithoutReadySubConns(t *testing.T) {
+	builders := map[string]base.PickerBuilder{
+		BalancerWeighted: &weightedPickerBuilder{},
+		BalancerP2C:      &p2cPickerBuilder{},
+		BalancerHash:     &hashPickerBuilder{},
+	}
+	for name, b := range builders {
+		p := b.Build(base.PickerBuildInfo{})
+		if _, err := p.Pick(balancer.PickInfo{Ctx: context.Background()}); err != balancer.ErrNoSubConnAvailable {
+			t.Errorf("%s: Pick() error = %v, want ErrNoSubConnAvailable", name, err)
+		}
+	}
+}
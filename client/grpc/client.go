@@ -2,9 +2,12 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
@@ -30,16 +33,29 @@ func New(opts ...Option) (*Client, error) {
 	var dialOpts []grpc.DialOption
 
 	// 设置安全选项
-	if !options.Secure {
+	switch {
+	case options.TLSConfig != nil:
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(options.TLSConfig)))
+	case options.Secure:
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})))
+	default:
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	// 添加拦截器
-	if len(options.UnaryInterceptors) > 0 {
-		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(options.UnaryInterceptors...))
+	// 添加拦截器，中间件拦截器始终位于最前，未配置中间件时仍会写入传输信息
+	unaryInterceptors := append([]grpc.UnaryClientInterceptor{UnaryClientInterceptor(options.Middleware...)}, options.UnaryInterceptors...)
+	streamInterceptors := append([]grpc.StreamClientInterceptor{StreamClientInterceptor(options.Middleware...)}, options.StreamInterceptors...)
+	dialOpts = append(dialOpts,
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
+	)
+
+	// 设置服务发现与负载均衡
+	if options.Registry != nil {
+		dialOpts = append(dialOpts, grpc.WithResolvers(NewResolverBuilder(options.Registry)))
 	}
-	if len(options.StreamInterceptors) > 0 {
-		dialOpts = append(dialOpts, grpc.WithChainStreamInterceptor(options.StreamInterceptors...))
+	if options.Balancer != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, options.Balancer)))
 	}
 
 	// 设置keepalive参数
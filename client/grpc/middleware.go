@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor 将通用中间件转换为一元客户端拦截器
+//
+// 调用前会向上下文写入 transport.KindGRPC 的客户端传输信息，中间件设置的
+// 请求头作为出站元数据发送，调用完成后响应头为服务端返回的响应元数据。
+func UnaryClientInterceptor(m ...middleware.Middleware) grpc.UnaryClientInterceptor {
+	chain := middleware.Chain(m...)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		tr := newTransport(ctx, cc.Target(), method)
+		next := func(ctx context.Context, req interface{}) (interface{}, error) {
+			ctx = tr.outgoingContext(ctx)
+			var header metadata.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+			for k, v := range header {
+				tr.replyHeader[k] = v
+			}
+			return reply, err
+		}
+		_, err := chain(next)(transport.NewClientContext(ctx, tr), req)
+		return err
+	}
+}
+
+// StreamClientInterceptor 将通用中间件转换为流式客户端拦截器
+//
+// 中间件在创建流时执行一次，请求为 nil，响应为 grpc.ClientStream。
+// 流式调用的响应元数据需通过 grpc.ClientStream 的 Header 方法获取。
+func StreamClientInterceptor(m ...middleware.Middleware) grpc.StreamClientInterceptor {
+	chain := middleware.Chain(m...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tr := newTransport(ctx, cc.Target(), method)
+		next := func(ctx context.Context, req interface{}) (interface{}, error) {
+			ctx = tr.outgoingContext(ctx)
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		resp, err := chain(next)(transport.NewClientContext(ctx, tr), nil)
+		if err != nil {
+			return nil, err
+		}
+		stream, _ := resp.(grpc.ClientStream)
+		return stream, nil
+	}
+}
+
+// grpcTransport gRPC客户端传输信息
+type grpcTransport struct {
+	endpoint    string
+	operation   string
+	reqHeader   transport.MetadataCarrier
+	replyHeader transport.MetadataCarrier
+}
+
+// newTransport 创建客户端传输信息，请求头为出站元数据的副本
+func newTransport(ctx context.Context, target, method string) *grpcTransport {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	return &grpcTransport{
+		endpoint:    target,
+		operation:   method,
+		reqHeader:   transport.MetadataCarrier(md),
+		replyHeader: transport.MetadataCarrier{},
+	}
+}
+
+// outgoingContext 将请求头合并到出站元数据，同名键以请求头为准
+func (t *grpcTransport) outgoingContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	for k, v := range t.reqHeader {
+		md[k] = v
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// Kind 实现 transport.Transporter 接口
+func (t *grpcTransport) Kind() transport.Kind {
+	return transport.KindGRPC
+}
+
+// Endpoint 实现 transport.Transporter 接口
+func (t *grpcTransport) Endpoint() string {
+	return t.endpoint
+}
+
+// Operation 实现 transport.Transporter 接口
+func (t *grpcTransport) Operation() string {
+	return t.operation
+}
+
+// RequestHeader 实现 transport.Transporter 接口
+func (t *grpcTransport) RequestHeader() transport.Header {
+	return t.reqHeader
+}
+
+// ReplyHeader 实现 transport.Transporter 接口
+func (t *grpcTransport) ReplyHeader() transport.Header {
+	return t.replyHeader
+}
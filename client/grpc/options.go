@@ -1,8 +1,11 @@
 package grpc
 
 import (
+	"crypto/tls"
 	"time"
 
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/registry"
 	"google.golang.org/grpc"
 )
 
@@ -12,9 +15,13 @@ type Options struct {
 	Timeout            time.Duration                  // 连接超时时间
 	KeepAlive          bool                           // 是否启用心跳
 	EnableHealthCheck  bool                           // 是否启用健康检查
-	Secure             bool                           // 是否启用安全连接
+	Secure             bool                           // 是否启用安全连接，未设置TLS配置时使用系统根证书
+	TLSConfig          *tls.Config                    // TLS配置，设置后启用安全连接
+	Middleware         []middleware.Middleware        // 中间件列表
 	UnaryInterceptors  []grpc.UnaryClientInterceptor  // 一元拦截器
 	StreamInterceptors []grpc.StreamClientInterceptor // 流式拦截器
+	Registry           registry.Registry              // 注册中心，设置后可使用 discovery:///<服务名称> 形式的目标地址
+	Balancer           string                         // 负载均衡策略，为空时使用 gRPC 默认的 pick_first
 }
 
 // Option 定义配置函数类型
@@ -55,6 +62,20 @@ func WithSecure(enable bool) Option {
 	}
 }
 
+// WithTLSConfig 设置TLS配置，可使用 tlsconfig.NewClientConfig 创建
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
+// WithMiddleware 添加中间件，中间件在拦截器之前执行
+func WithMiddleware(m ...middleware.Middleware) Option {
+	return func(o *Options) {
+		o.Middleware = append(o.Middleware, m...)
+	}
+}
+
 // WithUnaryInterceptors 添加一元拦截器
 func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
 	return func(o *Options) {
@@ -68,3 +89,17 @@ func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) Option
 		o.StreamInterceptors = append(o.StreamInterceptors, interceptors...)
 	}
 }
+
+// WithRegistry 设置注册中心，目标地址为 discovery:///<服务名称> 时通过注册中心解析
+func WithRegistry(r registry.Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+// WithBalancer 设置负载均衡策略，例如 BalancerRoundRobin、BalancerWeighted、BalancerP2C 与 BalancerHash
+func WithBalancer(name string) Option {
+	return func(o *Options) {
+		o.Balancer = name
+	}
+}
@@ -0,0 +1,218 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// Scheme 服务发现的目标地址前缀，例如 discovery:///user-service
+	Scheme = "discovery"
+	// WeightKey 服务实例元数据中的权重键，权重为正整数，未设置时为 DefaultWeight
+	WeightKey = "weight"
+	// DefaultWeight 默认权重
+	DefaultWeight = 100
+
+	// resolveTimeout 查询服务实例的超时时间
+	resolveTimeout = time.Second * 5
+	// retryInterval 监听失败后的重试间隔
+	retryInterval = time.Second
+)
+
+// weightKey 地址属性中权重的键，属性值参与地址比较，权重变化时会重建连接
+type weightKey struct{}
+
+// NewResolverBuilder 创建基于注册中心的解析器，解析 discovery:///<服务名称> 形式的目标地址
+//
+// 解析器通过 Registry.GetService 获取服务实例，之后使用 Registry.Watch 返回的实例列表更新地址。
+// 服务实例的地址取 Endpoints 中 grpc:// 前缀或不带前缀的地址，状态不是 registry.StatusUp 的实例被排除。
+func NewResolverBuilder(r registry.Registry) resolver.Builder {
+	return &resolverBuilder{registry: r}
+}
+
+// resolverBuilder 基于注册中心的解析器构建器
+type resolverBuilder struct {
+	registry registry.Registry
+}
+
+// Build 实现 resolver.Builder 接口
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	name := target.Endpoint()
+	if name == "" {
+		return nil, fmt.Errorf("grpc: invalid discovery target %q", target.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &discoveryResolver{
+		registry: b.registry,
+		name:     name,
+		cc:       cc,
+		ctx:      ctx,
+		cancel:   cancel,
+		resolve:  make(chan struct{}, 1),
+	}
+	r.wg.Add(2)
+	go r.watch()
+	go r.resolveLoop()
+	return r, nil
+}
+
+// Scheme 实现 resolver.Builder 接口
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// discoveryResolver 基于注册中心的解析器
+type discoveryResolver struct {
+	registry registry.Registry
+	name     string
+	cc       resolver.ClientConn
+	ctx      context.Context
+	cancel   context.CancelFunc
+	resolve  chan struct{}
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+}
+
+// ResolveNow 实现 resolver.Resolver 接口
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolve <- struct{}{}:
+	default:
+	}
+}
+
+// Close 实现 resolver.Resolver 接口
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// watch 监听服务变更，监听失败时按 retryInterval 重试
+func (r *discoveryResolver) watch() {
+	defer r.wg.Done()
+	for {
+		w, err := r.registry.Watch(r.ctx, r.name)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+			r.cc.ReportError(err)
+		} else {
+			r.update()
+			for {
+				instances, err := w.Next()
+				if err != nil {
+					break
+				}
+				r.apply(instances)
+			}
+			_ = w.Stop()
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// resolveLoop 处理 ResolveNow 触发的解析
+func (r *discoveryResolver) resolveLoop() {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.resolve:
+			r.update()
+		}
+	}
+}
+
+// update 获取服务实例并更新地址列表
+func (r *discoveryResolver) update() {
+	ctx, cancel := context.WithTimeout(r.ctx, resolveTimeout)
+	defer cancel()
+	instances, err := r.registry.GetService(ctx, r.name)
+	if err != nil {
+		if r.ctx.Err() == nil {
+			r.cc.ReportError(err)
+		}
+		return
+	}
+	r.apply(instances)
+}
+
+// apply 以服务实例更新地址列表
+func (r *discoveryResolver) apply(instances []*registry.ServiceInstance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	addrs := make([]resolver.Address, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Status != registry.StatusUp {
+			continue
+		}
+		addr, ok := grpcEndpoint(instance.Endpoints)
+		if !ok {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{
+			Addr:       addr,
+			Attributes: attributes.New(weightKey{}, instanceWeight(instance)),
+		})
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Addr < addrs[j].Addr })
+
+	// 没有可用实例时同样更新为空列表，使负载均衡器关闭原有连接，调用返回 Unavailable
+	err := r.cc.UpdateState(resolver.State{Addresses: addrs})
+	if err != nil && len(addrs) > 0 && r.ctx.Err() == nil {
+		r.cc.ReportError(err)
+	}
+}
+
+// grpcEndpoint 返回 gRPC 地址，优先使用 grpc:// 前缀的地址
+func grpcEndpoint(endpoints []string) (string, bool) {
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err == nil && u.Scheme == "grpc" && u.Host != "" {
+			return u.Host, true
+		}
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			// 不带前缀的 host:port 地址
+			return endpoint, true
+		}
+	}
+	return "", false
+}
+
+// instanceWeight 返回服务实例的权重
+func instanceWeight(instance *registry.ServiceInstance) int {
+	weight, err := strconv.Atoi(instance.Metadata[WeightKey])
+	if err != nil || weight <= 0 {
+		return DefaultWeight
+	}
+	return weight
+}
+
+// addressWeight 返回地址的权重
+func addressWeight(addr resolver.Address) int {
+	if weight, ok := addr.Attributes.Value(weightKey{}).(int); ok {
+		return weight
+	}
+	return DefaultWeight
+}
@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/registry/memory"
+	"google.golang.org/grpc/resolver"
+)
+
+// fakeClientConn 记录解析结果的 resolver.ClientConn
+type fakeClientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+	errs   chan error
+}
+
+func newFakeClientConn() *fakeClientConn {
+	return &fakeClientConn{
+		states: make(chan resolver.State, 16),
+		errs:   make(chan error, 16),
+	}
+}
+
+// UpdateState 实现 resolver.ClientConn 接口
+func (c *fakeClientConn) UpdateState(s resolver.State) error {
+	c.states <- s
+	return nil
+}
+
+// ReportError 实现 resolver.ClientConn 接口
+func (c *fakeClientConn) ReportError(err error) {
+	c.errs <- err
+}
+
+// next 等待下一次解析结果并返回地址列表
+func (c *fakeClientConn) next(t *testing.T) []string {
+	t.Helper()
+	select {
+	case s := <-c.states:
+		addrs := make([]string, 0, len(s.Addresses))
+		for _, a := range s.Addresses {
+			addrs = append(addrs, a.Addr)
+		}
+		return addrs
+	case err := <-c.errs:
+		t.Fatalf("resolver reported error: %v", err)
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out waiting for resolver state")
+	}
+	return nil
+}
+
+// buildResolver 创建解析 discovery:///svc 的解析器
+func buildResolver(t *testing.T, r registry.Registry) (*fakeClientConn, resolver.Resolver) {
+	t.Helper()
+	u, err := url.Parse("discovery:///svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := newFakeClientConn()
+	res, err := NewResolverBuilder(r).Build(resolver.Target{URL: *u}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	t.Cleanup(res.Close)
+	return cc, res
+}
+
+func instance(id, endpoint string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        id,
+		Name:      "svc",
+		Endpoints: []string{"http://127.0.0.1:8080", endpoint},
+	}
+}
+
+func TestResolverInitialResolve(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+	_ = r.Register(ctx, instance("a", "grpc://127.0.0.1:9001"))
+	_ = r.Register(ctx, instance("b", "127.0.0.1:9002"))
+
+	cc, _ := buildResolver(t, r)
+	got := cc.next(t)
+	want := []string{"127.0.0.1:9001", "127.0.0.1:9002"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses = %v, want %v", got, want)
+	}
+}
+
+func TestResolverWatchUpdates(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+	a := instance("a", "grpc://127.0.0.1:9001")
+	_ = r.Register(ctx, a)
+
+	cc, _ := buildResolver(t, r)
+	if got := cc.next(t); !reflect.DeepEqual(got, []string{"127.0.0.1:9001"}) {
+		t.Fatalf("initial addresses = %v", got)
+	}
+
+	_ = r.Register(ctx, instance("b", "grpc://127.0.0.1:9002"))
+	if got := cc.next(t); !reflect.DeepEqual(got, []string{"127.0.0.1:9001", "127.0.0.1:9002"}) {
+		t.Errorf("addresses after register = %v", got)
+	}
+
+	_ = r.SetStatus(ctx, a, registry.StatusDraining)
+	if got := cc.next(t); !reflect.DeepEqual(got, []string{"127.0.0.1:9002"}) {
+		t.Errorf("addresses after draining = %v, want draining instance excluded", got)
+	}
+}
+
+func TestResolverDeregisterRemovesAddress(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New()
+	a := instance("a", "grpc://127.0.0.1:9001")
+	b := instance("b", "grpc://127.0.0.1:9002")
+	_ = r.Register(ctx, a)
+	_ = r.Register(ctx, b)
+
+	cc, _ := buildResolver(t, r)
+	cc.next(t)
+
+	_ = r.Deregister(ctx, a)
+	if got := cc.next(t); !reflect.DeepEqual(got, []string{"127.0.0.1:9002"}) {
+		t.Errorf("addresses after deregister = %v", got)
+	}
+
+	_ = r.Deregister(ctx, b)
+	if got := cc.next(t); len(got) != 0 {
+		t.Errorf("addresses after deregistering all = %v, want empty", got)
+	}
+}
+
+func TestWeightFromMetadata(t *testing.T) {
+	tests := []struct {
+		metadata map[string]string
+		want     int
+	}{
+		{nil, DefaultWeight},
+		{map[string]string{WeightKey: "30"}, 30},
+		{map[string]string{WeightKey: "-1"}, DefaultWeight},
+		{map[string]string{WeightKey: "x"}, DefaultWeight},
+	}
+	for _, tt := range tests {
+		if got := instanceWeight(&registry.ServiceInstance{Metadata: tt.metadata}); got != tt.want {
+			t.Errorf("instanceWeight(%v) = %d, want %d", tt.metadata, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/app"
+	pb "github.com/huangsc/blade/examples/server/grpc/proto"
+	"github.com/huangsc/blade/server/grpc"
+	"github.com/huangsc/blade/server/http"
+)
+
+// userService 实现用户服务
+type userService struct {
+	pb.UnimplementedUserServiceServer
+}
+
+// GetUser 获取用户
+func (s *userService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	now := time.Now().Unix()
+	return &pb.User{
+		Id:       req.Id,
+		Name:     "测试用户",
+		Email:    "test@example.com",
+		CreateAt: now - 86400,
+		UpdateAt: now,
+	}, nil
+}
+
+func main() {
+	// 创建 HTTP 服务器
+	httpServer := http.New(
+		http.WithPort(8080),
+		http.WithMode(gin.ReleaseMode),
+	)
+	httpServer.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "UP"})
+	})
+
+	// 创建 gRPC 服务器
+	grpcServer := grpc.New(
+		grpc.WithPort(9000),
+		grpc.WithReflection(true),
+	)
+	pb.RegisterUserServiceServer(grpcServer.Server, &userService{})
+
+	// 创建应用
+	a := app.New(
+		app.WithName("user-service"),
+		app.WithVersion("v1.0.0"),
+		app.WithMetadata(map[string]string{"region": "cn-shanghai"}),
+		app.WithServers(httpServer, grpcServer),
+		app.WithStopTimeout(time.Second*5),
+		app.BeforeStart(func(ctx context.Context) error {
+			log.Println("应用启动前: 初始化资源")
+			return nil
+		}),
+		app.AfterStop(func(ctx context.Context) error {
+			log.Println("应用停止后: 释放资源")
+			return nil
+		}),
+	)
+
+	// 运行应用，收到 SIGINT/SIGTERM 后优雅关闭
+	if err := a.Run(); err != nil {
+		log.Fatalf("应用运行失败: %v", err)
+	}
+}
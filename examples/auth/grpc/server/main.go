@@ -123,10 +123,16 @@ func main() {
 		time.Hour*24,
 	)
 
+	// 认证拦截器选项：健康检查和反射服务无需认证，删除用户要求管理员角色
+	authOpts := []auth.InterceptorOption{
+		auth.WithSkipMethods(auth.HealthMethods, auth.ReflectionMethods),
+		auth.WithMethodRoles(pb.UserService_DeleteUser_FullMethodName, "admin"),
+	}
+
 	// 创建 gRPC 服务器
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(authenticator)),
-		grpc.StreamInterceptor(auth.StreamServerInterceptor(authenticator)),
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(authenticator, authOpts...)),
+		grpc.StreamInterceptor(auth.StreamServerInterceptor(authenticator, authOpts...)),
 	)
 
 	// 注册服务
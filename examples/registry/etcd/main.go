@@ -76,7 +76,7 @@ func main() {
 				services, err := watch.Next()
 				if err != nil {
 					log.Printf("监听服务变更失败: %v", err)
-					continue
+					return
 				}
 				log.Printf("服务列表更新: %d 个实例", len(services))
 				for _, svc := range services {
@@ -86,6 +86,23 @@ func main() {
 		}()
 	}
 
+	// 监听服务变更事件
+	events, err := registry.WatchEvents(ctx, r, service.Name)
+	if err != nil {
+		log.Printf("监听服务事件失败: %v", err)
+	} else {
+		go func() {
+			for {
+				event, err := events.Next()
+				if err != nil {
+					log.Printf("监听服务事件失败: %v", err)
+					return
+				}
+				log.Printf("服务事件: 类型=%s, ID=%s", event.Type, event.Service.ID)
+			}
+		}()
+	}
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/accesslog"
+	"github.com/huangsc/blade/logger"
 	"github.com/huangsc/blade/server/http"
 )
 
@@ -22,6 +24,10 @@ func main() {
 		http.WithHeaders(map[string]string{
 			"Server": "Knife/1.0",
 		}),
+		// 访问日志，跳过健康检查路径
+		http.WithMiddleware(accesslog.Middleware(logger.NewZapLogger(),
+			accesslog.WithSkipPaths("/v1/health"),
+		)),
 	)
 
 	// 注册路由
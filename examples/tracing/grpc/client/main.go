@@ -9,10 +9,8 @@ import (
 	pb "github.com/huangsc/blade/examples/tracing/grpc/proto"
 	"github.com/huangsc/blade/tracing"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
 )
 
 func main() {
@@ -27,9 +25,15 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tracer: %v", err)
 	}
-
-	// 连接 gRPC 服务器
-	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// 退出前导出剩余的 Span
+	defer tracer.Shutdown(context.Background())
+
+	// 连接 gRPC 服务器，追踪拦截器创建客户端 Span 并将追踪上下文注入 gRPC 元数据
+	conn, err := grpc.Dial("localhost:50051",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(tracer)),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor(tracer)),
+	)
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
@@ -47,31 +51,15 @@ func main() {
 func callUnaryRPC(tracer tracing.Tracer, client pb.HelloServiceClient) {
 	ctx := context.Background()
 
-	// 创建客户端 Span
+	// 创建调用方 Span，拦截器创建的客户端 Span 作为其子 Span
 	ctx, span := tracer.Start(ctx, "grpc.hello.say_hello",
-		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithSpanKind(tracing.SpanKindInternal),
 		tracing.WithSpanAttributes(
-			attribute.String("rpc.system", "grpc"),
-			attribute.String("rpc.service", "HelloService"),
-			attribute.String("rpc.method", "SayHello"),
+			attribute.String("hello.method", "SayHello"),
 		),
 	)
 	defer span.End()
 
-	// 注入追踪上下文到 gRPC 元数据
-	carrier := propagation.MapCarrier{}
-	if err := tracer.Inject(ctx, carrier); err != nil {
-		span.SetError(err)
-		log.Printf("Failed to inject context: %v", err)
-		return
-	}
-
-	md := metadata.New(nil)
-	for k, v := range carrier {
-		md.Set(k, v)
-	}
-	ctx = metadata.NewOutgoingContext(ctx, md)
-
 	// 发送请求
 	resp, err := client.SayHello(ctx, &pb.HelloRequest{Name: "Alice"})
 	if err != nil {
@@ -86,31 +74,15 @@ func callUnaryRPC(tracer tracing.Tracer, client pb.HelloServiceClient) {
 func callStreamingRPC(tracer tracing.Tracer, client pb.HelloServiceClient) {
 	ctx := context.Background()
 
-	// 创建客户端 Span
+	// 创建调用方 Span，拦截器创建的客户端 Span 作为其子 Span
 	ctx, span := tracer.Start(ctx, "grpc.hello.say_hello_stream",
-		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithSpanKind(tracing.SpanKindInternal),
 		tracing.WithSpanAttributes(
-			attribute.String("rpc.system", "grpc"),
-			attribute.String("rpc.service", "HelloService"),
-			attribute.String("rpc.method", "SayHelloStream"),
+			attribute.String("hello.method", "SayHelloStream"),
 		),
 	)
 	defer span.End()
 
-	// 注入追踪上下文到 gRPC 元数据
-	carrier := propagation.MapCarrier{}
-	if err := tracer.Inject(ctx, carrier); err != nil {
-		span.SetError(err)
-		log.Printf("Failed to inject context: %v", err)
-		return
-	}
-
-	md := metadata.New(nil)
-	for k, v := range carrier {
-		md.Set(k, v)
-	}
-	ctx = metadata.NewOutgoingContext(ctx, md)
-
 	// 发送请求
 	stream, err := client.SayHelloStream(ctx, &pb.HelloRequest{Name: "Bob"})
 	if err != nil {
@@ -10,9 +10,7 @@ import (
 	pb "github.com/huangsc/blade/examples/tracing/grpc/proto"
 	"github.com/huangsc/blade/tracing"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 )
 
 type server struct {
@@ -21,30 +19,9 @@ type server struct {
 }
 
 func (s *server) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloResponse, error) {
-	// 从 gRPC 元数据中提取追踪上下文
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		md = metadata.New(nil)
-	}
-	carrier := propagation.MapCarrier{}
-	for k, v := range md {
-		if len(v) > 0 {
-			carrier.Set(k, v[0])
-		}
-	}
-	ctx = s.tracer.Extract(ctx, carrier)
-
-	// 创建服务器端 Span
-	ctx, span := s.tracer.Start(ctx, "grpc.hello.say_hello",
-		tracing.WithSpanKind(tracing.SpanKindServer),
-		tracing.WithSpanAttributes(
-			attribute.String("rpc.system", "grpc"),
-			attribute.String("rpc.service", "HelloService"),
-			attribute.String("rpc.method", "SayHello"),
-			attribute.String("client.name", req.Name),
-		),
-	)
-	defer span.End()
+	// 追踪拦截器已提取追踪上下文并创建服务端 Span
+	span := tracing.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("client.name", req.Name))
 
 	// 处理请求
 	if err := processGRPCRequest(ctx, s.tracer, req.Name); err != nil {
@@ -60,30 +37,10 @@ func (s *server) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloR
 }
 
 func (s *server) SayHelloStream(req *pb.HelloRequest, stream pb.HelloService_SayHelloStreamServer) error {
-	// 从 gRPC 元数据中提取追踪上下文
-	md, ok := metadata.FromIncomingContext(stream.Context())
-	if !ok {
-		md = metadata.New(nil)
-	}
-	carrier := propagation.MapCarrier{}
-	for k, v := range md {
-		if len(v) > 0 {
-			carrier.Set(k, v[0])
-		}
-	}
-	ctx := s.tracer.Extract(stream.Context(), carrier)
-
-	// 创建服务器端 Span
-	ctx, span := s.tracer.Start(ctx, "grpc.hello.say_hello_stream",
-		tracing.WithSpanKind(tracing.SpanKindServer),
-		tracing.WithSpanAttributes(
-			attribute.String("rpc.system", "grpc"),
-			attribute.String("rpc.service", "HelloService"),
-			attribute.String("rpc.method", "SayHelloStream"),
-			attribute.String("client.name", req.Name),
-		),
-	)
-	defer span.End()
+	// 追踪拦截器已提取追踪上下文并创建服务端 Span
+	ctx := stream.Context()
+	span := tracing.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("client.name", req.Name))
 
 	// 发送多条响应
 	for i := 0; i < 5; i++ {
@@ -146,6 +103,8 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tracer: %v", err)
 	}
+	// 退出前导出剩余的 Span
+	defer tracer.Shutdown(context.Background())
 
 	// 启动 gRPC 服务器
 	lis, err := net.Listen("tcp", ":50051")
@@ -153,7 +112,10 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(tracer)),
+		grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor(tracer)),
+	)
 	pb.RegisterHelloServiceServer(s, &server{tracer: tracer})
 
 	log.Println("gRPC server is running on :50051")
@@ -34,6 +34,8 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tracer: %v", err)
 	}
+	// 退出前导出剩余的 Span
+	defer tracer.Shutdown(context.Background())
 
 	// 创建 Kafka 消费者
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
@@ -32,6 +32,8 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tracer: %v", err)
 	}
+	// 退出前导出剩余的 Span
+	defer tracer.Shutdown(context.Background())
 
 	// 创建 Kafka 生产者
 	producer, err := kafka.NewProducer(&kafka.ConfigMap{
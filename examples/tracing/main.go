@@ -25,6 +25,8 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tracer: %v", err)
 	}
+	// 退出前导出剩余的 Span
+	defer tracer.Shutdown(context.Background())
 
 	// 创建 HTTP 处理函数
 	http.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
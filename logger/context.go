@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextExtractor 从上下文中提取日志字段，用于 Logger.WithContext
+type ContextExtractor func(ctx context.Context) []Field
+
+// TraceExtractor 提取当前 Span 的 trace_id 与 span_id
+func TraceExtractor(ctx context.Context) []Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []Field{
+		String("trace_id", sc.TraceID().String()),
+		String("span_id", sc.SpanID().String()),
+	}
+}
+
+// BaggageExtractor 创建提取指定 baggage 成员的提取器，字段名为 baggage 键
+func BaggageExtractor(keys ...string) ContextExtractor {
+	return func(ctx context.Context) []Field {
+		b := baggage.FromContext(ctx)
+		var fields []Field
+		for _, k := range keys {
+			if v := b.Member(k).Value(); v != "" {
+				fields = append(fields, String(k, v))
+			}
+		}
+		return fields
+	}
+}
+
+// extractFields 依次调用提取器收集字段
+func extractFields(ctx context.Context, extractors []ContextExtractor) []Field {
+	if ctx == nil {
+		return nil
+	}
+	var fields []Field
+	for _, e := range extractors {
+		fields = append(fields, e(ctx)...)
+	}
+	return fields
+}
@@ -0,0 +1,277 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/huangsc/blade/config"
+)
+
+// ParseLevel 解析日志级别，不区分大小写
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("logger: unknown level %q", s)
+	}
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(l.String())), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Levels 可在运行时修改的日志级别，包括根级别与按模块配置的级别
+//
+// 模块名称为 Logger.Named 设置的名称，多级名称以点号连接，
+// 未配置级别的模块依次使用上级模块与根级别。
+type Levels struct {
+	root    atomic.Int32
+	mu      sync.Mutex
+	modules atomic.Pointer[map[string]Level]
+}
+
+// NewLevels 创建日志级别
+func NewLevels(root Level) *Levels {
+	l := &Levels{}
+	l.root.Store(int32(root))
+	l.modules.Store(&map[string]Level{})
+	return l
+}
+
+// Level 返回模块的日志级别，name 为空时返回根级别
+func (l *Levels) Level(name string) Level {
+	if modules := *l.modules.Load(); len(modules) > 0 {
+		for name != "" {
+			if level, ok := modules[name]; ok {
+				return level
+			}
+			i := strings.LastIndex(name, ".")
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return Level(l.root.Load())
+}
+
+// SetLevel 设置模块的日志级别，name 为空时设置根级别
+func (l *Levels) SetLevel(name string, level Level) {
+	if name == "" {
+		l.root.Store(int32(level))
+		return
+	}
+	l.update(func(modules map[string]Level) {
+		modules[name] = level
+	})
+}
+
+// Reset 移除模块的日志级别，使其使用上级模块或根级别
+func (l *Levels) Reset(name string) {
+	l.update(func(modules map[string]Level) {
+		delete(modules, name)
+	})
+}
+
+// Modules 返回按模块配置的日志级别
+func (l *Levels) Modules() map[string]Level {
+	modules := *l.modules.Load()
+	result := make(map[string]Level, len(modules))
+	for k, v := range modules {
+		result[k] = v
+	}
+	return result
+}
+
+// Set 按规格设置日志级别，例如 info,database=debug,mq=warn
+//
+// 不带模块名称的项设置根级别，未出现在规格中的模块级别会被移除。
+func (l *Levels) Set(spec string) error {
+	root := Level(l.root.Load())
+	modules := make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			level, err := ParseLevel(name)
+			if err != nil {
+				return err
+			}
+			root = level
+			continue
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		modules[strings.TrimSpace(name)] = level
+	}
+
+	l.mu.Lock()
+	l.modules.Store(&modules)
+	l.mu.Unlock()
+	l.root.Store(int32(root))
+	return nil
+}
+
+// String 返回日志级别规格，格式与 Set 相同
+func (l *Levels) String() string {
+	items := []string{strings.ToLower(Level(l.root.Load()).String())}
+	modules := *l.modules.Load()
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		items = append(items, name+"="+strings.ToLower(modules[name].String()))
+	}
+	return strings.Join(items, ",")
+}
+
+// update 以写时复制的方式修改模块级别
+func (l *Levels) update(fn func(map[string]Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modules := make(map[string]Level)
+	for k, v := range *l.modules.Load() {
+		modules[k] = v
+	}
+	fn(modules)
+	l.modules.Store(&modules)
+}
+
+// levelsPayload 日志级别HTTP接口的请求与响应
+type levelsPayload struct {
+	// Level 根级别
+	Level *Level `json:"level,omitempty"`
+	// Modules 模块级别，请求中值为空字符串时移除该模块的级别
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// ServeHTTP 实现 http.Handler 接口，GET 查询日志级别，PUT 修改日志级别
+//
+// 请求与响应体为 JSON，例如 {"level":"info","modules":{"database":"debug"}}。
+func (l *Levels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req levelsPayload
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeLevelsError(w, err)
+			return
+		}
+		modules := make(map[string]Level, len(req.Modules))
+		for name, value := range req.Modules {
+			if value == "" {
+				continue
+			}
+			level, err := ParseLevel(value)
+			if err != nil {
+				writeLevelsError(w, err)
+				return
+			}
+			modules[name] = level
+		}
+		if req.Level != nil {
+			l.SetLevel("", *req.Level)
+		}
+		for name, value := range req.Modules {
+			if value == "" {
+				l.Reset(name)
+			} else {
+				l.SetLevel(name, modules[name])
+			}
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	root := Level(l.root.Load())
+	resp := levelsPayload{Level: &root, Modules: make(map[string]string)}
+	for name, level := range l.Modules() {
+		resp.Modules[name] = strings.ToLower(level.String())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeLevelsError 返回错误响应
+func writeLevelsError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// Watch 使日志级别跟随配置项变化，配置值的格式与 Set 相同
+//
+// 配置项存在时立即应用，之后在后台监听变更直到 ctx 结束。
+// 无效的配置值会被忽略并保留当前级别。
+func (l *Levels) Watch(ctx context.Context, cfg config.Config, key string) error {
+	if v, err := cfg.Get(key); err == nil {
+		spec, err := v.String()
+		if err != nil {
+			return err
+		}
+		if err := l.Set(spec); err != nil {
+			return err
+		}
+	}
+
+	w, err := cfg.Watch(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		w.Stop()
+	}()
+	go func() {
+		for {
+			change, err := w.Next()
+			if err != nil {
+				return
+			}
+			if change.Type == config.Delete || change.Value == nil {
+				continue
+			}
+			if spec, err := change.Value.String(); err == nil {
+				_ = l.Set(spec)
+			}
+		}
+	}()
+	return nil
+}
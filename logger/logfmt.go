@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtEncoder logfmt 格式编码器，输出 key=value 形式的单行日志
+//
+// 时间、级别、名称、调用者与消息按固定顺序输出，其余字段按名称排序，
+// 嵌套对象与数组以 JSON 形式作为值输出。
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+	cfg zapcore.EncoderConfig
+}
+
+// bufferPool logfmt 编码器使用的缓冲池
+var bufferPool = buffer.NewPool()
+
+// newLogfmtEncoder 创建 logfmt 编码器
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		cfg:              cfg,
+	}
+}
+
+// Clone 实现 zapcore.Encoder 接口
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	return &logfmtEncoder{MapObjectEncoder: clone, cfg: e.cfg}
+}
+
+// EncodeEntry 实现 zapcore.Encoder 接口
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*logfmtEncoder)
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	buf := bufferPool.Get()
+	if e.cfg.TimeKey != "" {
+		appendPair(buf, e.cfg.TimeKey, e.formatTime(ent.Time))
+	}
+	if e.cfg.LevelKey != "" {
+		appendPair(buf, e.cfg.LevelKey, ent.Level.String())
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		appendPair(buf, e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined {
+		appendPair(buf, e.cfg.CallerKey, ent.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		appendPair(buf, e.cfg.MessageKey, ent.Message)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendPair(buf, k, formatValue(enc.Fields[k]))
+	}
+
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		appendPair(buf, e.cfg.StacktraceKey, ent.Stack)
+	}
+	buf.AppendString(zapcore.DefaultLineEnding)
+	return buf, nil
+}
+
+// formatTime 按编码器配置的时间格式输出时间
+func (e *logfmtEncoder) formatTime(t time.Time) string {
+	if e.cfg.EncodeTime == nil {
+		return t.Format(time.RFC3339Nano)
+	}
+	arr := &stringArrayEncoder{}
+	e.cfg.EncodeTime(t, arr)
+	if len(arr.values) == 0 {
+		return t.Format(time.RFC3339Nano)
+	}
+	return arr.values[0]
+}
+
+// appendPair 追加 key=value，值包含空白、等号或引号时加引号
+func appendPair(buf *buffer.Buffer, key, value string) {
+	if buf.Len() > 0 {
+		buf.AppendByte(' ')
+	}
+	buf.AppendString(key)
+	buf.AppendByte('=')
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n") {
+		buf.AppendString(fmt.Sprintf("%q", value))
+		return
+	}
+	buf.AppendString(value)
+}
+
+// formatValue 格式化字段值
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// stringArrayEncoder 收集时间编码器输出的字符串
+type stringArrayEncoder struct {
+	zapcore.PrimitiveArrayEncoder
+	values []string
+}
+
+// AppendString 实现 zapcore.PrimitiveArrayEncoder 接口
+func (a *stringArrayEncoder) AppendString(v string) {
+	a.values = append(a.values, v)
+}
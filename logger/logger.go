@@ -40,12 +40,19 @@ type Logger interface {
 	// Fatal 输出致命日志
 	Fatal(msg string, fields ...Field)
 
-	// WithContext 设置上下文
+	// WithContext 绑定上下文，返回的日志附加从上下文中提取的字段
 	WithContext(ctx context.Context) Logger
 	// WithFields 设置字段
 	WithFields(fields ...Field) Logger
-	// WithLevel 设置日志级别
+	// WithLevel 设置日志级别，返回的日志不再跟随运行时级别变化
 	WithLevel(level Level) Logger
+	// Named 创建命名子日志，子日志使用 Levels 中该模块的级别
+	Named(name string) Logger
+
+	// Sync 写出缓冲的日志
+	Sync() error
+	// Close 写出缓冲的日志并关闭日志打开的输出，不关闭 Sink.Writer，由同一实例派生的日志共享输出
+	Close() error
 }
 
 // Option 配置选项
@@ -73,6 +80,22 @@ type Options struct {
 	ErrorOutputPaths []string
 	// Development 是否为开发模式
 	Development bool
+	// ContextExtractors WithContext 使用的上下文字段提取器
+	ContextExtractors []ContextExtractor
+	// Levels 运行时日志级别，为 nil 时以 Level 为根级别创建
+	Levels *Levels
+	// Encoding 默认输出的编码格式，默认为 EncodingJSON，开发模式为 EncodingConsole
+	Encoding Encoding
+	// Rotate 默认输出的滚动文件配置，设置后忽略 OutputPaths
+	Rotate *RotateOptions
+	// Sinks 输出目标，设置后忽略 OutputPaths、Encoding 与 Rotate
+	Sinks []Sink
+	// Sampling 采样配置，为 nil 时不采样
+	Sampling *SamplingOptions
+	// BufferSize 异步写入的缓冲区大小，为 0 时同步写入
+	BufferSize int
+	// FlushInterval 异步写入的刷新间隔
+	FlushInterval time.Duration
 }
 
 // WithLevel 设置日志级别
@@ -133,6 +156,20 @@ func WithDevelopment(development bool) Option {
 	}
 }
 
+// WithLevels 设置运行时日志级别，设置后忽略 Level
+func WithLevels(levels *Levels) Option {
+	return func(o *Options) {
+		o.Levels = levels
+	}
+}
+
+// WithContextExtractors 添加上下文字段提取器，例如请求ID或租户
+func WithContextExtractors(extractors ...ContextExtractor) Option {
+	return func(o *Options) {
+		o.ContextExtractors = append(o.ContextExtractors, extractors...)
+	}
+}
+
 // String 返回日志级别字符串
 func (l Level) String() string {
 	switch l {
@@ -195,3 +232,60 @@ func Duration(key string, value time.Duration) Field {
 func Error(err error) Field {
 	return Field{Key: "error", Value: err}
 }
+
+// WithSinks 添加日志输出目标，设置后忽略 OutputPaths 与 Rotate
+//
+// 例如将错误日志以 JSON 格式单独写入文件：
+//
+//	logger.WithSinks(
+//		logger.Sink{Encoding: logger.EncodingConsole, Paths: []string{"stdout"}},
+//		logger.Sink{Levels: []logger.Level{logger.ErrorLevel, logger.FatalLevel}, Rotate: &logger.RotateOptions{Filename: "error.log"}},
+//	)
+func WithSinks(sinks ...Sink) Option {
+	return func(o *Options) {
+		o.Sinks = append(o.Sinks, sinks...)
+	}
+}
+
+// WithEncoding 设置默认输出的编码格式
+func WithEncoding(encoding Encoding) Option {
+	return func(o *Options) {
+		o.Encoding = encoding
+	}
+}
+
+// WithRotate 设置默认输出为滚动文件，设置后忽略 OutputPaths
+func WithRotate(opts RotateOptions) Option {
+	return func(o *Options) {
+		o.Rotate = &opts
+	}
+}
+
+// WithSampling 设置采样，每个 tick 内相同级别与消息的日志先输出 first 条，之后每 thereafter 条输出一条
+func WithSampling(tick time.Duration, first, thereafter int) Option {
+	return func(o *Options) {
+		o.Sampling = &SamplingOptions{
+			Tick:       tick,
+			First:      first,
+			Thereafter: thereafter,
+		}
+	}
+}
+
+// WithAsync 设置异步缓冲写入，缓冲区满或每隔 interval 时写出，退出前应调用 Logger.Close
+func WithAsync(size int, interval time.Duration) Option {
+	return func(o *Options) {
+		o.BufferSize = size
+		o.FlushInterval = interval
+	}
+}
+
+// SamplingOptions 日志采样配置
+type SamplingOptions struct {
+	// Tick 采样周期
+	Tick time.Duration
+	// First 每个周期内相同日志先输出的条数
+	First int
+	// Thereafter 超过 First 后每多少条输出一条，为 0 时丢弃其余日志
+	Thereafter int
+}
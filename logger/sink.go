@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Encoding 日志编码格式
+type Encoding string
+
+const (
+	// EncodingJSON JSON 格式
+	EncodingJSON Encoding = "json"
+	// EncodingConsole 控制台格式
+	EncodingConsole Encoding = "console"
+	// EncodingLogfmt logfmt 格式，即 key=value 形式
+	EncodingLogfmt Encoding = "logfmt"
+)
+
+// Sink 日志输出目标
+//
+// Rotate、Writer 与 Paths 按顺序取第一个已设置的作为输出。
+type Sink struct {
+	// Encoding 编码格式，默认为 EncodingJSON
+	Encoding Encoding
+	// Levels 输出的日志级别，为空时输出所有级别
+	Levels []Level
+	// Paths 输出路径，支持 stdout、stderr 与文件路径
+	Paths []string
+	// Writer 自定义输出，由调用方负责关闭，Close 只写出缓冲的日志
+	Writer io.Writer
+	// Rotate 滚动文件输出
+	Rotate *RotateOptions
+}
+
+// RotateOptions 日志文件滚动配置
+type RotateOptions struct {
+	// Filename 文件路径
+	Filename string
+	// MaxSize 单个文件的最大大小，单位MB，默认为 100
+	MaxSize int
+	// MaxAge 旧文件的保留天数，为 0 时不按时间清理
+	MaxAge int
+	// MaxBackups 旧文件的保留个数，为 0 时不按个数清理
+	MaxBackups int
+	// Compress 是否使用 gzip 压缩旧文件
+	Compress bool
+	// LocalTime 旧文件名中的时间是否使用本地时间，默认使用 UTC
+	LocalTime bool
+	// Interval 按时间滚动的间隔，例如 24 * time.Hour，为 0 时只按大小滚动
+	Interval time.Duration
+}
+
+// RotateWriter 滚动文件输出
+//
+// 文件大小超过 MaxSize 或到达 Interval 整数倍的时刻时滚动，
+// 旧文件按 MaxAge 与 MaxBackups 清理。
+type RotateWriter struct {
+	logger *lumberjack.Logger
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// NewRotateWriter 创建滚动文件输出
+func NewRotateWriter(opts RotateOptions) *RotateWriter {
+	w := &RotateWriter{
+		logger: &lumberjack.Logger{
+			Filename:   opts.Filename,
+			MaxSize:    opts.MaxSize,
+			MaxAge:     opts.MaxAge,
+			MaxBackups: opts.MaxBackups,
+			LocalTime:  opts.LocalTime,
+			Compress:   opts.Compress,
+		},
+		stop: make(chan struct{}),
+	}
+	if opts.Interval > 0 {
+		go w.rotateEvery(opts.Interval)
+	}
+	return w
+}
+
+// Write 实现 io.Writer 接口
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	return w.logger.Write(p)
+}
+
+// Sync 实现 zapcore.WriteSyncer 接口，写入不经过缓冲，无需刷新
+func (w *RotateWriter) Sync() error {
+	return nil
+}
+
+// Rotate 立即滚动日志文件
+func (w *RotateWriter) Rotate() error {
+	return w.logger.Rotate()
+}
+
+// Close 停止按时间滚动并关闭文件
+func (w *RotateWriter) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	return w.logger.Close()
+}
+
+// rotateEvery 在每个间隔的整数倍时刻滚动日志文件
+func (w *RotateWriter) rotateEvery(interval time.Duration) {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(interval).Add(interval).Sub(now))
+		select {
+		case <-timer.C:
+			_ = w.logger.Rotate()
+		case <-w.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// outputs 日志输出的刷新与关闭
+type outputs struct {
+	mu      sync.Mutex
+	syncers []zapcore.WriteSyncer
+	closers []func() error
+	closed  bool
+}
+
+// add 添加输出
+func (o *outputs) add(ws zapcore.WriteSyncer, closer func() error) {
+	o.syncers = append(o.syncers, ws)
+	if closer != nil {
+		o.closers = append(o.closers, closer)
+	}
+}
+
+// sync 刷新所有输出
+func (o *outputs) sync() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return nil
+	}
+	var errs []error
+	for _, ws := range o.syncers {
+		if err := ws.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close 刷新并关闭所有输出，重复调用无效果
+func (o *outputs) close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	var errs []error
+	for _, ws := range o.syncers {
+		if err := ws.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := len(o.closers) - 1; i >= 0; i-- {
+		if err := o.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newCore 根据输出目标创建 zap core
+func newCore(sink Sink, encoderConfig zapcore.EncoderConfig, options *Options, out *outputs) (zapcore.Core, error) {
+	var (
+		ws     zapcore.WriteSyncer
+		closer func() error
+	)
+	switch {
+	case sink.Rotate != nil:
+		w := NewRotateWriter(*sink.Rotate)
+		ws, closer = w, w.Close
+	case sink.Writer != nil:
+		// 只关闭日志自身打开的输出，调用方传入的 Writer 可能在其他地方继续使用，如 os.Stdout
+		ws = zapcore.AddSync(sink.Writer)
+	default:
+		paths := sink.Paths
+		if len(paths) == 0 {
+			paths = []string{"stdout"}
+		}
+		w, cleanup, err := zap.Open(paths...)
+		if err != nil {
+			return nil, err
+		}
+		ws = w
+		closer = func() error {
+			cleanup()
+			return nil
+		}
+	}
+
+	if options.BufferSize > 0 {
+		buffered := &zapcore.BufferedWriteSyncer{
+			WS:            ws,
+			Size:          options.BufferSize,
+			FlushInterval: options.FlushInterval,
+		}
+		ws = buffered
+		inner := closer
+		closer = func() error {
+			err := buffered.Stop()
+			if inner != nil {
+				if cerr := inner(); err == nil {
+					err = cerr
+				}
+			}
+			return err
+		}
+	}
+	out.add(ws, closer)
+
+	var enabler zapcore.LevelEnabler = zapcore.DebugLevel
+	if len(sink.Levels) > 0 {
+		levels := make(map[zapcore.Level]bool, len(sink.Levels))
+		for _, level := range sink.Levels {
+			levels[convertLevel(level)] = true
+		}
+		enabler = zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+			return levels[level]
+		})
+	}
+	return zapcore.NewCore(newEncoder(sink.Encoding, encoderConfig), ws, enabler), nil
+}
+
+// newEncoder 创建编码器
+func newEncoder(encoding Encoding, cfg zapcore.EncoderConfig) zapcore.Encoder {
+	switch encoding {
+	case EncodingConsole:
+		return zapcore.NewConsoleEncoder(cfg)
+	case EncodingLogfmt:
+		return newLogfmtEncoder(cfg)
+	default:
+		return zapcore.NewJSONEncoder(cfg)
+	}
+}
@@ -9,22 +9,32 @@ import (
 )
 
 type zapLogger struct {
-	logger *zap.Logger
-	level  Level
-	fields []Field
-	ctx    context.Context
+	logger     *zap.Logger
+	base       *zap.Logger // 不含上下文字段，WithContext 基于它重建 logger
+	levels     *Levels
+	outputs    *outputs
+	name       string
+	level      *Level
+	fields     []Field
+	ctx        context.Context
+	extractors []ContextExtractor
 }
 
 // NewZapLogger 创建一个基于 zap 的日志实现
+//
+// WithContext 默认附加 trace_id 与 span_id，可通过 WithContextExtractors 添加其他字段。
+// 日志级别可通过 WithLevels 传入的 Levels 在运行时修改。
+// 使用滚动文件或异步写入时，退出前应调用 Close 写出缓冲的日志并关闭文件。
 func NewZapLogger(opts ...Option) Logger {
 	options := &Options{
-		Level:            InfoLevel,
-		TimeFormat:       "2006-01-02 15:04:05.000",
-		EnableCaller:     true,
-		CallerSkip:       1,
-		Development:      false,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
+		Level:             InfoLevel,
+		TimeFormat:        "2006-01-02 15:04:05.000",
+		EnableCaller:      true,
+		CallerSkip:        1,
+		Development:       false,
+		OutputPaths:       []string{"stdout"},
+		ErrorOutputPaths:  []string{"stderr"},
+		ContextExtractors: []ContextExtractor{TraceExtractor},
 	}
 
 	for _, opt := range opts {
@@ -46,31 +56,62 @@ func NewZapLogger(opts ...Option) Logger {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	// 创建 zap 配置
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(convertLevel(options.Level)),
-		Development:      options.Development,
-		Encoding:         "json",
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      options.OutputPaths,
-		ErrorOutputPaths: options.ErrorOutputPaths,
+	levels := options.Levels
+	if levels == nil {
+		levels = NewLevels(options.Level)
 	}
 
-	if options.Development {
-		config.Encoding = "console"
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	// 创建输出目标，级别过滤由 Levels 完成
+	sinks := options.Sinks
+	if len(sinks) == 0 {
+		sink := Sink{
+			Encoding: options.Encoding,
+			Paths:    options.OutputPaths,
+			Rotate:   options.Rotate,
+		}
+		if options.Development && sink.Encoding == "" {
+			sink.Encoding = EncodingConsole
+		}
+		sinks = []Sink{sink}
 	}
 
-	// 创建 zap logger
-	logger, err := config.Build(
+	out := &outputs{}
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		cfg := encoderConfig
+		if options.Development && sink.Encoding == EncodingConsole && sink.Rotate == nil && sink.Writer == nil {
+			cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		core, err := newCore(sink, cfg, options, out)
+		if err != nil {
+			_ = out.close()
+			panic(err)
+		}
+		cores = append(cores, core)
+	}
+	core := zapcore.NewTee(cores...)
+	if options.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, options.Sampling.Tick, options.Sampling.First, options.Sampling.Thereafter)
+	}
+
+	errorOutput, _, err := zap.Open(options.ErrorOutputPaths...)
+	if err != nil {
+		_ = out.close()
+		panic(err)
+	}
+	zapOpts := []zap.Option{
+		zap.ErrorOutput(errorOutput),
 		zap.AddCaller(),
 		zap.AddCallerSkip(options.CallerSkip),
 		zap.AddStacktrace(convertLevel(options.StacktraceLevel)),
-	)
-	if err != nil {
-		panic(err)
+	}
+	if options.Development {
+		zapOpts = append(zapOpts, zap.Development())
 	}
 
+	// 创建 zap logger
+	logger := zap.New(core, zapOpts...)
+
 	// 添加全局字段
 	if len(options.Fields) > 0 {
 		fields := make([]zap.Field, 0, len(options.Fields))
@@ -81,42 +122,53 @@ func NewZapLogger(opts ...Option) Logger {
 	}
 
 	return &zapLogger{
-		logger: logger,
-		level:  options.Level,
-		fields: options.Fields,
+		logger:     logger,
+		base:       logger,
+		levels:     levels,
+		outputs:    out,
+		fields:     options.Fields,
+		extractors: options.ContextExtractors,
+	}
+}
+
+// enabled 判断日志级别是否启用
+func (l *zapLogger) enabled(level Level) bool {
+	if l.level != nil {
+		return level >= *l.level
 	}
+	return level >= l.levels.Level(l.name)
 }
 
 func (l *zapLogger) Debug(msg string, fields ...Field) {
-	if l.level > DebugLevel {
+	if !l.enabled(DebugLevel) {
 		return
 	}
 	l.logger.Debug(msg, convertFields(fields...)...)
 }
 
 func (l *zapLogger) Info(msg string, fields ...Field) {
-	if l.level > InfoLevel {
+	if !l.enabled(InfoLevel) {
 		return
 	}
 	l.logger.Info(msg, convertFields(fields...)...)
 }
 
 func (l *zapLogger) Warn(msg string, fields ...Field) {
-	if l.level > WarnLevel {
+	if !l.enabled(WarnLevel) {
 		return
 	}
 	l.logger.Warn(msg, convertFields(fields...)...)
 }
 
 func (l *zapLogger) Error(msg string, fields ...Field) {
-	if l.level > ErrorLevel {
+	if !l.enabled(ErrorLevel) {
 		return
 	}
 	l.logger.Error(msg, convertFields(fields...)...)
 }
 
 func (l *zapLogger) Fatal(msg string, fields ...Field) {
-	if l.level > FatalLevel {
+	if !l.enabled(FatalLevel) {
 		return
 	}
 	l.logger.Fatal(msg, convertFields(fields...)...)
@@ -124,31 +176,54 @@ func (l *zapLogger) Fatal(msg string, fields ...Field) {
 }
 
 func (l *zapLogger) WithContext(ctx context.Context) Logger {
-	return &zapLogger{
-		logger: l.logger,
-		level:  l.level,
-		fields: l.fields,
-		ctx:    ctx,
+	c := l.clone()
+	c.logger = l.base
+	if fields := extractFields(ctx, l.extractors); len(fields) > 0 {
+		c.logger = c.logger.With(convertFields(fields...)...)
 	}
+	c.ctx = ctx
+	return c
 }
 
 func (l *zapLogger) WithFields(fields ...Field) Logger {
-	newLogger := l.logger.With(convertFields(fields...)...)
-	return &zapLogger{
-		logger: newLogger,
-		level:  l.level,
-		fields: append(l.fields, fields...),
-		ctx:    l.ctx,
-	}
+	c := l.clone()
+	zapFields := convertFields(fields...)
+	c.logger = c.logger.With(zapFields...)
+	c.base = c.base.With(zapFields...)
+	c.fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	return c
 }
 
 func (l *zapLogger) WithLevel(level Level) Logger {
-	return &zapLogger{
-		logger: l.logger,
-		level:  level,
-		fields: l.fields,
-		ctx:    l.ctx,
+	c := l.clone()
+	c.level = &level
+	return c
+}
+
+func (l *zapLogger) Named(name string) Logger {
+	c := l.clone()
+	c.logger = c.logger.Named(name)
+	c.base = c.base.Named(name)
+	if l.name != "" {
+		c.name = l.name + "." + name
+	} else {
+		c.name = name
 	}
+	return c
+}
+
+func (l *zapLogger) Sync() error {
+	return l.outputs.sync()
+}
+
+func (l *zapLogger) Close() error {
+	return l.outputs.close()
+}
+
+// clone 复制日志实例
+func (l *zapLogger) clone() *zapLogger {
+	c := *l
+	return &c
 }
 
 // convertLevel 转换日志级别
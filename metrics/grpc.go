@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 创建一元 RPC 服务端指标拦截器
+func UnaryServerInterceptor(r *RequestMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		r.observeGRPC(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 创建流式 RPC 服务端指标拦截器，处理时长为整个流的时长
+func StreamServerInterceptor(r *RequestMetrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		r.observeGRPC(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// UnaryClientInterceptor 创建一元 RPC 客户端指标拦截器
+func UnaryClientInterceptor(r *RequestMetrics) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.observeGRPC(method, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor 创建流式 RPC 客户端指标拦截器
+//
+// 流在接收消息返回错误或 io.EOF 时记录，服务端只返回一条消息时在收到该消息后记录，
+// 调用方未读取到流结束就取消上下文时随上下文记录。处理时长为从创建流到结束的时长。
+func StreamClientInterceptor(r *RequestMetrics) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			r.observeGRPC(method, err, time.Since(start))
+			return nil, err
+		}
+		return newObservedClientStream(ctx, stream, desc, func(err error) {
+			r.observeGRPC(method, err, time.Since(start))
+		}), nil
+	}
+}
+
+// observeGRPC 记录一次 gRPC 调用，服务端错误类状态码计为错误
+func (r *RequestMetrics) observeGRPC(method string, err error, latency time.Duration) {
+	code := status.Code(err)
+	r.Observe(method, code.String(), transport.IsGRPCServerError(code), latency)
+}
+
+// observedClientStream 在流结束时记录指标的客户端流
+type observedClientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	once          sync.Once
+	stop          func() bool
+	observe       func(err error)
+}
+
+// newObservedClientStream 创建客户端流，ctx 结束时记录指标
+//
+// 使用调用方的上下文而不是 stream.Context()，后者在流正常结束时同样会被取消。
+func newObservedClientStream(ctx context.Context, stream grpc.ClientStream, desc *grpc.StreamDesc, observe func(err error)) *observedClientStream {
+	s := &observedClientStream{
+		ClientStream:  stream,
+		serverStreams: desc.ServerStreams,
+		observe:       observe,
+	}
+	s.stop = context.AfterFunc(ctx, func() {
+		s.once.Do(func() {
+			s.observe(status.FromContextError(ctx.Err()).Err())
+		})
+	})
+	return s
+}
+
+// RecvMsg 实现 grpc.ClientStream 接口
+func (s *observedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		if !s.serverStreams {
+			s.end(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	default:
+		s.end(err)
+	}
+	return err
+}
+
+// end 停止监听上下文并记录指标，只执行一次
+func (s *observedClientStream) end(err error) {
+	s.stop()
+	s.once.Do(func() {
+		s.observe(err)
+	})
+}
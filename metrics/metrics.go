@@ -31,6 +31,15 @@ type Metrics interface {
 	Histogram(name string, labels Labels) HistogramMetric
 	// Summary 摘要
 	Summary(name string, labels Labels) SummaryMetric
+
+	// CounterVec 只声明标签名称的计数器，不创建任何标签组合，需通过 WithLabels 使用
+	CounterVec(name string, labelNames ...string) CounterMetric
+	// GaugeVec 只声明标签名称的仪表盘，不创建任何标签组合，需通过 WithLabels 使用
+	GaugeVec(name string, labelNames ...string) GaugeMetric
+	// HistogramVec 只声明标签名称的直方图，不创建任何标签组合，需通过 WithLabels 使用
+	HistogramVec(name string, labelNames ...string) HistogramMetric
+	// SummaryVec 只声明标签名称的摘要，不创建任何标签组合，需通过 WithLabels 使用
+	SummaryVec(name string, labelNames ...string) SummaryMetric
 }
 
 // CounterMetric 计数器接口
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware 创建HTTP请求指标中间件
+//
+// 未匹配路由的请求操作名称中的路由模板为空，避免路径导致标签基数膨胀。
+func Middleware(r *RequestMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		r.observeHTTP(c.Request.Method+" "+c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// observeHTTP 记录一次HTTP请求，5xx 状态码计为错误
+func (r *RequestMetrics) observeHTTP(operation string, code int, latency time.Duration) {
+	r.Observe(operation, strconv.Itoa(code), code >= http.StatusInternalServerError, latency)
+}
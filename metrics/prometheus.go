@@ -1,7 +1,10 @@
 package metrics
 
 import (
+	"net/http"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type prometheusMetrics struct {
@@ -54,17 +57,14 @@ func NewPrometheusMetrics(opts ...Option) Metrics {
 	}
 }
 
-func (p *prometheusMetrics) Counter(name string, labels Labels) CounterMetric {
-	opts := prometheus.CounterOpts{
-		Namespace:   p.namespace,
-		Subsystem:   p.subsystem,
-		Name:        name,
-		Help:        name,
-		ConstLabels: prometheus.Labels(p.labels),
-	}
+// Handler 返回 Prometheus 指标的HTTP处理器
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
 
+func (p *prometheusMetrics) Counter(name string, labels Labels) CounterMetric {
 	if len(labels) == 0 {
-		counter := prometheus.NewCounter(opts)
+		counter := prometheus.NewCounter(p.counterOpts(name))
 		prometheus.MustRegister(counter)
 		return &prometheusCounter{counter: counter}
 	}
@@ -74,8 +74,7 @@ func (p *prometheusMetrics) Counter(name string, labels Labels) CounterMetric {
 		labelNames = append(labelNames, name)
 	}
 
-	vec := prometheus.NewCounterVec(opts, labelNames)
-	prometheus.MustRegister(vec)
+	vec := p.counterVec(name, labelNames)
 	counter, err := vec.GetMetricWith(prometheus.Labels(labels))
 	if err != nil {
 		panic(err)
@@ -87,17 +86,13 @@ func (p *prometheusMetrics) Counter(name string, labels Labels) CounterMetric {
 	}
 }
 
-func (p *prometheusMetrics) Gauge(name string, labels Labels) GaugeMetric {
-	opts := prometheus.GaugeOpts{
-		Namespace:   p.namespace,
-		Subsystem:   p.subsystem,
-		Name:        name,
-		Help:        name,
-		ConstLabels: prometheus.Labels(p.labels),
-	}
+func (p *prometheusMetrics) CounterVec(name string, labelNames ...string) CounterMetric {
+	return &prometheusCounter{vec: p.counterVec(name, labelNames)}
+}
 
+func (p *prometheusMetrics) Gauge(name string, labels Labels) GaugeMetric {
 	if len(labels) == 0 {
-		gauge := prometheus.NewGauge(opts)
+		gauge := prometheus.NewGauge(p.gaugeOpts(name))
 		prometheus.MustRegister(gauge)
 		return &prometheusGauge{gauge: gauge}
 	}
@@ -107,8 +102,7 @@ func (p *prometheusMetrics) Gauge(name string, labels Labels) GaugeMetric {
 		labelNames = append(labelNames, name)
 	}
 
-	vec := prometheus.NewGaugeVec(opts, labelNames)
-	prometheus.MustRegister(vec)
+	vec := p.gaugeVec(name, labelNames)
 	gauge, err := vec.GetMetricWith(prometheus.Labels(labels))
 	if err != nil {
 		panic(err)
@@ -120,17 +114,13 @@ func (p *prometheusMetrics) Gauge(name string, labels Labels) GaugeMetric {
 	}
 }
 
-func (p *prometheusMetrics) Histogram(name string, labels Labels) HistogramMetric {
-	opts := prometheus.HistogramOpts{
-		Namespace:   p.namespace,
-		Subsystem:   p.subsystem,
-		Name:        name,
-		Help:        name,
-		ConstLabels: prometheus.Labels(p.labels),
-	}
+func (p *prometheusMetrics) GaugeVec(name string, labelNames ...string) GaugeMetric {
+	return &prometheusGauge{vec: p.gaugeVec(name, labelNames)}
+}
 
+func (p *prometheusMetrics) Histogram(name string, labels Labels) HistogramMetric {
 	if len(labels) == 0 {
-		histogram := prometheus.NewHistogram(opts)
+		histogram := prometheus.NewHistogram(p.histogramOpts(name))
 		prometheus.MustRegister(histogram)
 		return &prometheusHistogram{histogram: histogram}
 	}
@@ -140,8 +130,7 @@ func (p *prometheusMetrics) Histogram(name string, labels Labels) HistogramMetri
 		labelNames = append(labelNames, name)
 	}
 
-	vec := prometheus.NewHistogramVec(opts, labelNames)
-	prometheus.MustRegister(vec)
+	vec := p.histogramVec(name, labelNames)
 	histogram, err := vec.GetMetricWith(prometheus.Labels(labels))
 	if err != nil {
 		panic(err)
@@ -153,17 +142,13 @@ func (p *prometheusMetrics) Histogram(name string, labels Labels) HistogramMetri
 	}
 }
 
-func (p *prometheusMetrics) Summary(name string, labels Labels) SummaryMetric {
-	opts := prometheus.SummaryOpts{
-		Namespace:   p.namespace,
-		Subsystem:   p.subsystem,
-		Name:        name,
-		Help:        name,
-		ConstLabels: prometheus.Labels(p.labels),
-	}
+func (p *prometheusMetrics) HistogramVec(name string, labelNames ...string) HistogramMetric {
+	return &prometheusHistogram{vec: p.histogramVec(name, labelNames)}
+}
 
+func (p *prometheusMetrics) Summary(name string, labels Labels) SummaryMetric {
 	if len(labels) == 0 {
-		summary := prometheus.NewSummary(opts)
+		summary := prometheus.NewSummary(p.summaryOpts(name))
 		prometheus.MustRegister(summary)
 		return &prometheusSummary{summary: summary}
 	}
@@ -173,8 +158,7 @@ func (p *prometheusMetrics) Summary(name string, labels Labels) SummaryMetric {
 		labelNames = append(labelNames, name)
 	}
 
-	vec := prometheus.NewSummaryVec(opts, labelNames)
-	prometheus.MustRegister(vec)
+	vec := p.summaryVec(name, labelNames)
 	summary, err := vec.GetMetricWith(prometheus.Labels(labels))
 	if err != nil {
 		panic(err)
@@ -186,6 +170,82 @@ func (p *prometheusMetrics) Summary(name string, labels Labels) SummaryMetric {
 	}
 }
 
+func (p *prometheusMetrics) SummaryVec(name string, labelNames ...string) SummaryMetric {
+	return &prometheusSummary{vec: p.summaryVec(name, labelNames)}
+}
+
+// counterOpts 返回指定名称的计数器配置
+func (p *prometheusMetrics) counterOpts(name string) prometheus.CounterOpts {
+	return prometheus.CounterOpts{
+		Namespace:   p.namespace,
+		Subsystem:   p.subsystem,
+		Name:        name,
+		Help:        name,
+		ConstLabels: prometheus.Labels(p.labels),
+	}
+}
+
+// counterVec 创建并注册计数器向量
+func (p *prometheusMetrics) counterVec(name string, labelNames []string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(p.counterOpts(name), labelNames)
+	prometheus.MustRegister(vec)
+	return vec
+}
+
+// gaugeOpts 返回指定名称的仪表盘配置
+func (p *prometheusMetrics) gaugeOpts(name string) prometheus.GaugeOpts {
+	return prometheus.GaugeOpts{
+		Namespace:   p.namespace,
+		Subsystem:   p.subsystem,
+		Name:        name,
+		Help:        name,
+		ConstLabels: prometheus.Labels(p.labels),
+	}
+}
+
+// gaugeVec 创建并注册仪表盘向量
+func (p *prometheusMetrics) gaugeVec(name string, labelNames []string) *prometheus.GaugeVec {
+	vec := prometheus.NewGaugeVec(p.gaugeOpts(name), labelNames)
+	prometheus.MustRegister(vec)
+	return vec
+}
+
+// histogramOpts 返回指定名称的直方图配置
+func (p *prometheusMetrics) histogramOpts(name string) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Namespace:   p.namespace,
+		Subsystem:   p.subsystem,
+		Name:        name,
+		Help:        name,
+		ConstLabels: prometheus.Labels(p.labels),
+	}
+}
+
+// histogramVec 创建并注册直方图向量
+func (p *prometheusMetrics) histogramVec(name string, labelNames []string) *prometheus.HistogramVec {
+	vec := prometheus.NewHistogramVec(p.histogramOpts(name), labelNames)
+	prometheus.MustRegister(vec)
+	return vec
+}
+
+// summaryOpts 返回指定名称的摘要配置
+func (p *prometheusMetrics) summaryOpts(name string) prometheus.SummaryOpts {
+	return prometheus.SummaryOpts{
+		Namespace:   p.namespace,
+		Subsystem:   p.subsystem,
+		Name:        name,
+		Help:        name,
+		ConstLabels: prometheus.Labels(p.labels),
+	}
+}
+
+// summaryVec 创建并注册摘要向量
+func (p *prometheusMetrics) summaryVec(name string, labelNames []string) *prometheus.SummaryVec {
+	vec := prometheus.NewSummaryVec(p.summaryOpts(name), labelNames)
+	prometheus.MustRegister(vec)
+	return vec
+}
+
 func (c *prometheusCounter) Inc() {
 	c.counter.Inc()
 }
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"time"
+)
+
+// RequestMetrics 请求的 RED 指标，包括请求数、错误数与处理时长
+//
+// 指标标签为 operation 与 code，HTTP 的操作名称为请求方法与路由模板，
+// 例如 GET /v1/users/:id，gRPC 的操作名称为完整方法名。
+type RequestMetrics struct {
+	requests CounterMetric
+	errors   CounterMetric
+	latency  HistogramMetric
+}
+
+// NewRequestMetrics 创建请求指标
+//
+// 指标名称以 prefix 为前缀，例如 prefix 为 http_server 时创建
+// http_server_requests_total、http_server_errors_total 与
+// http_server_request_duration_seconds。同一前缀在同一 Metrics 上只能创建一次。
+func NewRequestMetrics(m Metrics, prefix string) *RequestMetrics {
+	return &RequestMetrics{
+		requests: m.CounterVec(prefix+"_requests_total", "operation", "code"),
+		errors:   m.CounterVec(prefix+"_errors_total", "operation", "code"),
+		latency:  m.HistogramVec(prefix+"_request_duration_seconds", "operation", "code"),
+	}
+}
+
+// Observe 记录一次请求
+func (r *RequestMetrics) Observe(operation, code string, failed bool, latency time.Duration) {
+	labels := Labels{"operation": operation, "code": code}
+	r.requests.WithLabels(labels).Inc()
+	if failed {
+		r.errors.WithLabels(labels).Inc()
+	}
+	r.latency.WithLabels(labels).Observe(latency.Seconds())
+}
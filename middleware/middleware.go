@@ -2,6 +2,11 @@ package middleware
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/huangsc/blade/logger"
+	"github.com/huangsc/blade/transport"
 )
 
 // Handler 定义中间件处理函数
@@ -20,13 +25,57 @@ func Chain(middlewares ...Middleware) Middleware {
 	}
 }
 
-// Recovery 定义恢复中间件
-func Recovery() Middleware {
+// PanicError 处理请求时发生的 panic
+type PanicError struct {
+	// Value panic 的值
+	Value interface{}
+	// Stack 发生 panic 时的调用栈
+	Stack []byte
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap 返回 panic 的值为 error 时的原始错误
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RecoveryOptions 恢复中间件配置选项
+type RecoveryOptions struct {
+	// Logger 记录 panic 的值与调用栈，为 nil 时不记录
+	Logger logger.Logger
+}
+
+// RecoveryOption 定义恢复中间件配置函数类型
+type RecoveryOption func(*RecoveryOptions)
+
+// WithRecoveryLogger 设置记录 panic 的日志记录器
+func WithRecoveryLogger(l logger.Logger) RecoveryOption {
+	return func(o *RecoveryOptions) {
+		o.Logger = l
+	}
+}
+
+// Recovery 定义恢复中间件，将任意 panic 转换为带调用栈的 *PanicError
+//
+// panic 的详细信息只写入日志，HTTP 与 gRPC 适配器向客户端返回通用的内部错误。
+func Recovery(opts ...RecoveryOption) Middleware {
+	options := &RecoveryOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
 	return func(next Handler) Handler {
 		return func(ctx context.Context, req interface{}) (resp interface{}, err error) {
 			defer func() {
 				if r := recover(); r != nil {
-					err = recoverError(r)
+					pe := recoverError(r)
+					options.log(ctx, pe)
+					err = pe
 				}
 			}()
 			return next(ctx, req)
@@ -34,10 +83,25 @@ func Recovery() Middleware {
 	}
 }
 
-// recoverError 将 panic 转换为 error
-func recoverError(r interface{}) error {
-	if err, ok := r.(error); ok {
-		return err
+// log 记录 panic
+func (o *RecoveryOptions) log(ctx context.Context, pe *PanicError) {
+	if o.Logger == nil {
+		return
+	}
+	fields := []logger.Field{
+		logger.Any("panic", pe.Value),
+		logger.String("stack", string(pe.Stack)),
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		fields = append(fields, logger.String("operation", tr.Operation()))
+	}
+	o.Logger.WithContext(ctx).Error("panic recovered", fields...)
+}
+
+// recoverError 将 panic 转换为 *PanicError
+func recoverError(r interface{}) *PanicError {
+	return &PanicError{
+		Value: r,
+		Stack: debug.Stack(),
 	}
-	return nil
 }
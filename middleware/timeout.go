@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/huangsc/blade/transport"
+)
+
+// TimeoutOptions 超时中间件配置选项
+type TimeoutOptions struct {
+	// Operations 按操作名称配置的超时时间，为 0 时该操作只受 MaxTimeout 限制
+	Operations map[string]time.Duration
+	// MaxTimeout 超时时间与调用方截止时间的上限，为 0 时不限制
+	MaxTimeout time.Duration
+}
+
+// TimeoutOption 定义超时配置函数类型
+type TimeoutOption func(*TimeoutOptions)
+
+// WithOperationTimeout 设置指定操作的超时时间
+//
+// 操作名称为 transport.Transporter 的 Operation，HTTP 为路由模板，gRPC 为完整方法名。
+func WithOperationTimeout(operation string, timeout time.Duration) TimeoutOption {
+	return func(o *TimeoutOptions) {
+		if o.Operations == nil {
+			o.Operations = make(map[string]time.Duration)
+		}
+		o.Operations[operation] = timeout
+	}
+}
+
+// WithMaxTimeout 设置超时时间与调用方截止时间的上限
+func WithMaxTimeout(max time.Duration) TimeoutOption {
+	return func(o *TimeoutOptions) {
+		o.MaxTimeout = max
+	}
+}
+
+// Timeout 定义超时中间件，通过上下文截止时间限制处理时长
+//
+// 超时时间为操作对应的超时时间，未配置时使用 timeout，并以 MaxTimeout 为上限。
+// 上下文已有截止时间（如 gRPC 调用方设置的截止时间）时取两者中较早的一个。
+// 截止时间随上下文传递给下游调用，处理函数返回时截止时间已过则返回 context.DeadlineExceeded。
+//
+// 中间件不会在截止时间到达时中断处理函数，处理函数需要检查 ctx.Done()
+// 或将 ctx 传递给下游调用，才能在超时后及时返回。
+func Timeout(timeout time.Duration, opts ...TimeoutOption) Middleware {
+	options := &TimeoutOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			d := options.timeout(ctx, timeout)
+			if d <= 0 {
+				return next(ctx, req)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			resp, err := next(ctx, req)
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, context.DeadlineExceeded
+			}
+			return resp, err
+		}
+	}
+}
+
+// timeout 返回请求应使用的超时时间，为 0 时不设置超时
+//
+// 调用方的截止时间由 context.WithTimeout 与返回值合并，这里只计算服务端的超时时间。
+func (o *TimeoutOptions) timeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if d, ok := o.Operations[tr.Operation()]; ok {
+			timeout = d
+		}
+	}
+	if o.MaxTimeout > 0 && (timeout <= 0 || timeout > o.MaxTimeout) {
+		return o.MaxTimeout
+	}
+	return timeout
+}
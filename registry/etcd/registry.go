@@ -3,10 +3,13 @@ package etcd
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"path"
 	"sync"
 	"time"
 
+	"github.com/huangsc/blade/logger"
 	"github.com/huangsc/blade/registry"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -16,26 +19,37 @@ const (
 	defaultTTL = time.Second * 15
 	// defaultRegisterTimeout 默认注册超时时间
 	defaultRegisterTimeout = time.Second * 5
-	// defaultWatchTimeout 默认监听超时时间
-	defaultWatchTimeout = time.Second * 5
+	// minRetryInterval 重新注册的最小退避间隔
+	minRetryInterval = time.Second
+	// maxRetryInterval 重新注册的最大退避间隔
+	maxRetryInterval = time.Second * 30
+	// maxStatusAttempts 实例被并发修改时更新状态的最大尝试次数
+	maxStatusAttempts = 3
 )
 
+// errLeaseLost 续约中断时的错误
+var errLeaseLost = errors.New("registry: etcd lease keepalive stopped")
+
 // Registry etcd注册中心
+//
+// 每个服务实例使用独立的租约与续约，续约失败时按退避间隔重新注册。
 type Registry struct {
-	client      *clientv3.Client
-	prefix      string          // 服务前缀
-	ttl         time.Duration   // 服务TTL
-	ctx         context.Context // 根上下文
-	cancel      context.CancelFunc
-	leaseID     clientv3.LeaseID // 租约ID
-	keepAliveCh <-chan *clientv3.LeaseKeepAliveResponse
-	mutex       sync.Mutex
+	client        *clientv3.Client
+	opts          *Options
+	ctx           context.Context // 根上下文
+	cancel        context.CancelFunc
+	registrations map[string]*registration // 服务键 -> 注册信息
+	mutex         sync.Mutex               // 保护 registrations
+	regMutex      sync.Mutex               // 串行化注册与注销
 }
 
 // Options 配置选项
 type Options struct {
-	Prefix string        // 服务前缀
-	TTL    time.Duration // 服务TTL
+	Prefix           string                                             // 服务前缀
+	TTL              time.Duration                                      // 服务TTL，服务实例未设置 TTL 时使用
+	Logger           logger.Logger                                      // 日志记录器，记录租约丢失与恢复
+	OnLeaseLost      func(service *registry.ServiceInstance, err error) // 租约丢失时调用
+	OnLeaseRecovered func(service *registry.ServiceInstance)            // 重新注册成功时调用
 }
 
 // Option 定义配置函数类型
@@ -55,6 +69,27 @@ func WithTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithLogger 设置日志记录器
+func WithLogger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithOnLeaseLost 设置租约丢失时的回调
+func WithOnLeaseLost(fn func(service *registry.ServiceInstance, err error)) Option {
+	return func(o *Options) {
+		o.OnLeaseLost = fn
+	}
+}
+
+// WithOnLeaseRecovered 设置重新注册成功时的回调
+func WithOnLeaseRecovered(fn func(service *registry.ServiceInstance)) Option {
+	return func(o *Options) {
+		o.OnLeaseRecovered = fn
+	}
+}
+
 // New 创建etcd注册中心
 func New(client *clientv3.Client, opts ...Option) (*Registry, error) {
 	options := &Options{
@@ -67,15 +102,15 @@ func New(client *clientv3.Client, opts ...Option) (*Registry, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Registry{
-		client: client,
-		prefix: options.Prefix,
-		ttl:    options.TTL,
-		ctx:    ctx,
-		cancel: cancel,
+		client:        client,
+		opts:          options,
+		ctx:           ctx,
+		cancel:        cancel,
+		registrations: make(map[string]*registration),
 	}, nil
 }
 
-// Register 注册服务
+// Register 注册服务，重复注册同一实例时替换原有注册
 func (r *Registry) Register(ctx context.Context, service *registry.ServiceInstance) error {
 	key := r.serviceKey(service)
 	value, err := json.Marshal(service)
@@ -83,68 +118,171 @@ func (r *Registry) Register(ctx context.Context, service *registry.ServiceInstan
 		return err
 	}
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	// 创建租约
-	grant, err := r.client.Grant(ctx, int64(r.ttl.Seconds()))
-	if err != nil {
-		return err
+	ttl := service.TTL
+	if ttl <= 0 {
+		ttl = r.opts.TTL
+	}
+	if ttl < time.Second {
+		ttl = time.Second
 	}
 
-	// 注册服务
-	_, err = r.client.Put(ctx, key, string(value), clientv3.WithLease(grant.ID))
-	if err != nil {
-		return err
+	// 注册与注销串行执行，网络请求期间不持有 r.mutex
+	r.regMutex.Lock()
+	defer r.regMutex.Unlock()
+
+	// 先停止原有注册，避免其重新注册时覆盖新的租约
+	r.mutex.Lock()
+	old := r.registrations[key]
+	delete(r.registrations, key)
+	r.mutex.Unlock()
+	if old != nil {
+		old.stop()
 	}
 
-	// 保持租约
-	keepAliveCh, err := r.client.KeepAlive(r.ctx, grant.ID)
+	regCtx, cancel := context.WithCancel(r.ctx)
+	reg := &registration{
+		registry: r,
+		service:  service,
+		key:      key,
+		value:    string(value),
+		ttl:      ttl,
+		ctx:      regCtx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	keepAliveCh, err := reg.register(ctx)
 	if err != nil {
+		cancel()
 		return err
 	}
 
-	r.leaseID = grant.ID
-	r.keepAliveCh = keepAliveCh
+	r.mutex.Lock()
+	r.registrations[key] = reg
+	r.mutex.Unlock()
+	go reg.keepAlive(keepAliveCh)
 
-	// 处理续约响应
-	go func() {
-		for {
-			select {
-			case _, ok := <-keepAliveCh:
-				if !ok {
-					return
-				}
-			case <-r.ctx.Done():
-				return
+	// 服务键已绑定到新租约，撤销原租约不会删除服务键
+	if old != nil {
+		if leaseID := old.lease(); leaseID != 0 {
+			if _, err := r.client.Revoke(ctx, leaseID); err != nil {
+				r.logError("registry: revoke replaced lease failed", service, err)
 			}
 		}
-	}()
-
+	}
 	return nil
 }
 
-// Deregister 注销服务
+// Deregister 注销服务，撤销该实例的租约
 func (r *Registry) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	key := r.serviceKey(service)
+
+	r.regMutex.Lock()
+	defer r.regMutex.Unlock()
+
 	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	reg, ok := r.registrations[key]
+	delete(r.registrations, key)
+	r.mutex.Unlock()
+
+	if !ok {
+		_, err := r.client.Delete(ctx, key)
+		return err
+	}
 
-	// 撤销租约
-	if r.leaseID != 0 {
-		_, err := r.client.Revoke(ctx, r.leaseID)
+	reg.stop()
+	if leaseID := reg.lease(); leaseID != 0 {
+		if _, err := r.client.Revoke(ctx, leaseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetStatus 更新实例状态，保留原有租约，可更新其他进程注册的实例
+func (r *Registry) SetStatus(ctx context.Context, service *registry.ServiceInstance, status registry.Status) error {
+	return r.setStatus(ctx, service, nil, status)
+}
+
+// CompareAndSetStatus 实例当前状态为 old 时更新状态，保留原有租约
+func (r *Registry) CompareAndSetStatus(ctx context.Context, service *registry.ServiceInstance, old, status registry.Status) error {
+	return r.setStatus(ctx, service, &old, status)
+}
+
+// setStatus 更新实例状态，expected 不为 nil 时要求当前状态与之一致
+//
+// 写入以读取时的修订版本为条件，实例在读取后被修改时重新读取并比较状态。
+func (r *Registry) setStatus(ctx context.Context, service *registry.ServiceInstance, expected *registry.Status, status registry.Status) error {
+	key := r.serviceKey(service)
+	for attempt := 0; ; attempt++ {
+		resp, err := r.client.Get(ctx, key)
 		if err != nil {
 			return err
 		}
+		if len(resp.Kvs) == 0 {
+			return registry.ErrNotFound
+		}
+
+		instance := &registry.ServiceInstance{}
+		if err := json.Unmarshal(resp.Kvs[0].Value, instance); err != nil {
+			return err
+		}
+		if expected != nil && instance.Status != *expected {
+			return registry.ErrStatusChanged
+		}
+		if instance.Status == status {
+			return nil
+		}
+		instance.Status = status
+		value, err := json.Marshal(instance)
+		if err != nil {
+			return err
+		}
+
+		txn := r.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(key, string(value), clientv3.WithIgnoreLease()))
+		txnResp, err := txn.Commit()
+		if err != nil {
+			return err
+		}
+		if !txnResp.Succeeded {
+			if attempt+1 < maxStatusAttempts {
+				continue
+			}
+			return fmt.Errorf("registry: service instance %s changed concurrently", key)
+		}
+
+		r.syncValue(key, string(value))
+		return nil
+	}
+}
+
+// syncValue 同步本进程注册的实例，重新注册时使用新状态
+func (r *Registry) syncValue(key, value string) {
+
+	r.mutex.Lock()
+	if reg, ok := r.registrations[key]; ok {
+		reg.setValue(value)
 	}
+	r.mutex.Unlock()
+}
 
+// Close 停止所有实例的续约，租约在 TTL 后过期
+func (r *Registry) Close() error {
 	r.cancel()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key, reg := range r.registrations {
+		<-reg.done
+		delete(r.registrations, key)
+	}
 	return nil
 }
 
 // GetService 获取服务实例列表
 func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
-	key := path.Join(r.prefix, serviceName)
-	resp, err := r.client.Get(ctx, key, clientv3.WithPrefix())
+	resp, err := r.client.Get(ctx, r.servicePrefix(serviceName), clientv3.WithPrefix())
 	if err != nil {
 		return nil, err
 	}
@@ -160,13 +298,157 @@ func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*regis
 	return items, nil
 }
 
-// Watch 监听服务变更
+// Watch 监听服务变更，每次变更后 Next 返回完整的实例列表
 func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
-	key := path.Join(r.prefix, serviceName)
-	return newWatcher(ctx, key, r.client), nil
+	return newWatcher(ctx, r.servicePrefix(serviceName), r.client)
+}
+
+// servicePrefix 生成服务实例键的前缀，以 / 结尾以免匹配名称前缀相同的其他服务
+func (r *Registry) servicePrefix(serviceName string) string {
+	return path.Join(r.opts.Prefix, serviceName) + "/"
 }
 
 // serviceKey 生成服务键
 func (r *Registry) serviceKey(service *registry.ServiceInstance) string {
-	return path.Join(r.prefix, service.Name, service.ID)
+	return path.Join(r.opts.Prefix, service.Name, service.ID)
+}
+
+// registration 服务实例的注册信息
+type registration struct {
+	registry *Registry
+	service  *registry.ServiceInstance
+	key      string
+	value    string
+	ttl      time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mutex    sync.Mutex
+	leaseID  clientv3.LeaseID
+}
+
+// register 创建租约并写入服务实例，返回续约响应通道
+func (g *registration) register(ctx context.Context) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	c := g.registry.client
+	grant, err := c.Grant(ctx, int64(g.ttl/time.Second))
+	if err != nil {
+		return nil, err
+	}
+	g.mutex.Lock()
+	value := g.value
+	g.mutex.Unlock()
+	if _, err := c.Put(ctx, g.key, value, clientv3.WithLease(grant.ID)); err != nil {
+		_, _ = c.Revoke(context.Background(), grant.ID)
+		return nil, err
+	}
+	keepAliveCh, err := c.KeepAlive(g.ctx, grant.ID)
+	if err != nil {
+		_, _ = c.Revoke(context.Background(), grant.ID)
+		return nil, err
+	}
+
+	g.mutex.Lock()
+	g.leaseID = grant.ID
+	g.mutex.Unlock()
+	return keepAliveCh, nil
+}
+
+// keepAlive 处理续约响应，续约通道关闭时视为租约丢失并重新注册
+func (g *registration) keepAlive(keepAliveCh <-chan *clientv3.LeaseKeepAliveResponse) {
+	defer close(g.done)
+	for {
+		// 丢弃续约响应，通道在租约过期或注册停止时关闭
+		for range keepAliveCh {
+		}
+		if g.ctx.Err() != nil {
+			return
+		}
+
+		g.registry.leaseLost(g.service, errLeaseLost)
+		keepAliveCh = g.reregister()
+		if keepAliveCh == nil {
+			return
+		}
+		g.registry.leaseRecovered(g.service)
+	}
+}
+
+// reregister 按退避间隔重新注册直到成功，注册被停止时返回 nil
+func (g *registration) reregister() <-chan *clientv3.LeaseKeepAliveResponse {
+	interval := minRetryInterval
+	for {
+		select {
+		case <-g.ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		ctx, cancel := context.WithTimeout(g.ctx, defaultRegisterTimeout)
+		keepAliveCh, err := g.register(ctx)
+		cancel()
+		if err == nil {
+			return keepAliveCh
+		}
+		if g.ctx.Err() != nil {
+			return nil
+		}
+		g.registry.logError("registry: re-register service failed", g.service, err)
+
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// setValue 更新重新注册时写入的值
+func (g *registration) setValue(value string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.value = value
+}
+
+// lease 返回当前租约ID
+func (g *registration) lease() clientv3.LeaseID {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.leaseID
+}
+
+// stop 停止续约与重新注册
+func (g *registration) stop() {
+	g.cancel()
+	<-g.done
+}
+
+// leaseLost 处理租约丢失
+func (r *Registry) leaseLost(service *registry.ServiceInstance, err error) {
+	r.logError("registry: service lease lost", service, err)
+	if r.opts.OnLeaseLost != nil {
+		r.opts.OnLeaseLost(service, err)
+	}
+}
+
+// leaseRecovered 处理重新注册成功
+func (r *Registry) leaseRecovered(service *registry.ServiceInstance) {
+	if r.opts.Logger != nil {
+		r.opts.Logger.Info("registry: service re-registered",
+			logger.String("service", service.Name),
+			logger.String("id", service.ID),
+		)
+	}
+	if r.opts.OnLeaseRecovered != nil {
+		r.opts.OnLeaseRecovered(service)
+	}
+}
+
+// logError 输出错误日志
+func (r *Registry) logError(msg string, service *registry.ServiceInstance, err error) {
+	if r.opts.Logger != nil {
+		r.opts.Logger.Error(msg,
+			logger.String("service", service.Name),
+			logger.String("id", service.ID),
+			logger.Error(err),
+		)
+	}
 }
@@ -0,0 +1,74 @@
+package etcd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/registry/registrytest"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// newServer 启动嵌入式 etcd 服务并返回客户端
+func newServer(t *testing.T) *clientv3.Client {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL, peerURL := freeURL(t), freeURL(t)
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("StartEtcd() error = %v", err)
+	}
+	t.Cleanup(server.Close)
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(time.Second * 30):
+		t.Fatal("etcd server not ready")
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{clientURL.Host},
+		DialTimeout: time.Second * 5,
+	})
+	if err != nil {
+		t.Fatalf("clientv3.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// freeURL 返回本机空闲端口的地址
+func freeURL(t *testing.T) url.URL {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+func TestRegistry(t *testing.T) {
+	client := newServer(t)
+	var n atomic.Int64
+	registrytest.Run(t, func(t *testing.T) registry.Registry {
+		r, err := New(client, WithPrefix(fmt.Sprintf("/registrytest/%d", n.Add(1))))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		t.Cleanup(func() { _ = r.Close() })
+		return r
+	})
+}
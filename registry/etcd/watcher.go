@@ -3,54 +3,80 @@ package etcd
 import (
 	"context"
 	"encoding/json"
+	"sort"
 
 	"github.com/huangsc/blade/registry"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
 // watcher 实现了 registry.Watcher 接口
+//
+// 创建时读取服务的全部实例，之后将 etcd 的变更应用到本地实例列表，
+// 修订版本被压缩或监听中断时重新读取全部实例并从新的修订版本继续监听。
 type watcher struct {
-	key    string
-	client *clientv3.Client
-	ctx    context.Context
-	cancel context.CancelFunc
-	wch    clientv3.WatchChan
+	key       string
+	client    *clientv3.Client
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wch       clientv3.WatchChan
+	stopWatch context.CancelFunc
+	instances map[string]*registry.ServiceInstance // etcd 键 -> 实例
 }
 
 // newWatcher 创建新的 watcher
-func newWatcher(ctx context.Context, key string, client *clientv3.Client) registry.Watcher {
+func newWatcher(ctx context.Context, key string, client *clientv3.Client) (registry.Watcher, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	w := &watcher{
 		key:    key,
 		client: client,
 		ctx:    ctx,
 		cancel: cancel,
-		wch:    client.Watch(ctx, key, clientv3.WithPrefix()),
 	}
-	return w
+	if err := w.resync(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return w, nil
 }
 
 // Next 实现 registry.Watcher 接口
 func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
-	select {
-	case <-w.ctx.Done():
-		return nil, w.ctx.Err()
-	case wresp := <-w.wch:
-		if wresp.Err() != nil {
-			return nil, wresp.Err()
-		}
-		var items []*registry.ServiceInstance
-		for _, ev := range wresp.Events {
-			si := &registry.ServiceInstance{}
-			if ev.Type == clientv3.EventTypeDelete {
-				continue
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case wresp, ok := <-w.wch:
+			if !ok || wresp.Canceled || wresp.CompactRevision != 0 || wresp.Err() != nil {
+				if w.ctx.Err() != nil {
+					return nil, w.ctx.Err()
+				}
+				if err := w.resync(); err != nil {
+					return nil, err
+				}
+				return w.snapshot(), nil
+			}
+
+			changed := false
+			for _, ev := range wresp.Events {
+				key := string(ev.Kv.Key)
+				if ev.Type == clientv3.EventTypeDelete {
+					if _, ok := w.instances[key]; ok {
+						delete(w.instances, key)
+						changed = true
+					}
+					continue
+				}
+				si := &registry.ServiceInstance{}
+				if err := json.Unmarshal(ev.Kv.Value, si); err != nil {
+					return nil, err
+				}
+				w.instances[key] = si
+				changed = true
 			}
-			if err := json.Unmarshal(ev.Kv.Value, si); err != nil {
-				return nil, err
+			if changed {
+				return w.snapshot(), nil
 			}
-			items = append(items, si)
 		}
-		return items, nil
 	}
 }
 
@@ -59,3 +85,40 @@ func (w *watcher) Stop() error {
 	w.cancel()
 	return nil
 }
+
+// resync 读取全部实例，并从读取时的修订版本之后开始监听
+func (w *watcher) resync() error {
+	resp, err := w.client.Get(w.ctx, w.key, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	instances := make(map[string]*registry.ServiceInstance, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		si := &registry.ServiceInstance{}
+		if err := json.Unmarshal(kv.Value, si); err != nil {
+			return err
+		}
+		instances[string(kv.Key)] = si
+	}
+	w.instances = instances
+
+	// 停止之前的监听
+	if w.stopWatch != nil {
+		w.stopWatch()
+	}
+	ctx, cancel := context.WithCancel(w.ctx)
+	w.stopWatch = cancel
+	w.wch = w.client.Watch(ctx, w.key, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+	return nil
+}
+
+// snapshot 返回当前的实例列表，按实例ID排序
+func (w *watcher) snapshot() []*registry.ServiceInstance {
+	items := make([]*registry.ServiceInstance, 0, len(w.instances))
+	for _, si := range w.instances {
+		items = append(items, si)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
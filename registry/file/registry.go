@@ -0,0 +1,237 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/registry/memory"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultInterval 默认文件检查间隔
+const defaultInterval = time.Second * 5
+
+// Instance 文件中的服务实例
+type Instance struct {
+	ID        string            `json:"id" yaml:"id"`               // 实例ID，为空时按服务名称与序号生成
+	Name      string            `json:"name" yaml:"name"`           // 服务名称
+	Version   string            `json:"version" yaml:"version"`     // 服务版本
+	Metadata  map[string]string `json:"metadata" yaml:"metadata"`   // 服务元数据
+	Endpoints []string          `json:"endpoints" yaml:"endpoints"` // 服务地址列表
+	Status    string            `json:"status" yaml:"status"`       // 服务状态，为空时为 up
+}
+
+// Options 配置选项
+type Options struct {
+	Interval time.Duration // 文件检查间隔
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithInterval 设置文件检查间隔
+func WithInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = interval
+	}
+}
+
+// Registry 文件注册中心，服务实例从 JSON 或 YAML 文件读取，适用于没有 etcd 的环境
+//
+// 文件内容为 Instance 列表，根据扩展名选择格式，按间隔检查文件修改时间，变更时重新读取。
+// 通过 Register 注册的实例只保存在当前进程中，不会写入文件。
+type Registry struct {
+	path   string
+	store  *memory.Registry
+	loaded map[string]*registry.ServiceInstance // 文件中的实例，键为服务名称与实例ID
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+// New 创建文件注册中心，首次读取文件失败时返回错误
+func New(path string, opts ...Option) (*Registry, error) {
+	options := &Options{
+		Interval: defaultInterval,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Registry{
+		path:   path,
+		store:  memory.New(),
+		loaded: make(map[string]*registry.ServiceInstance),
+		cancel: cancel,
+	}
+	if err := r.Reload(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go r.watchFile(ctx, info.ModTime(), options.Interval)
+	return r, nil
+}
+
+// Register 注册服务，实例只保存在当前进程中
+func (r *Registry) Register(ctx context.Context, service *registry.ServiceInstance) error {
+	return r.store.Register(ctx, service)
+}
+
+// Deregister 注销服务
+func (r *Registry) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	return r.store.Deregister(ctx, service)
+}
+
+// SetStatus 更新实例状态，状态只保存在当前进程中，文件中的实例发生变化时被覆盖
+func (r *Registry) SetStatus(ctx context.Context, service *registry.ServiceInstance, status registry.Status) error {
+	return r.store.SetStatus(ctx, service, status)
+}
+
+// CompareAndSetStatus 实例当前状态为 old 时更新状态，状态只保存在当前进程中
+func (r *Registry) CompareAndSetStatus(ctx context.Context, service *registry.ServiceInstance, old, status registry.Status) error {
+	return r.store.CompareAndSetStatus(ctx, service, old, status)
+}
+
+// GetService 获取服务实例列表
+func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return r.store.GetService(ctx, serviceName)
+}
+
+// Watch 监听服务变更，文件中的实例变化时 Next 返回当前的实例列表
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return r.store.Watch(ctx, serviceName)
+}
+
+// Reload 重新读取文件，只有发生变化的服务会通知监听器
+func (r *Registry) Reload(ctx context.Context) error {
+	instances, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	loaded := make(map[string]*registry.ServiceInstance, len(instances))
+	for _, instance := range instances {
+		loaded[instance.Name+"/"+instance.ID] = instance
+	}
+	for key, old := range r.loaded {
+		if _, ok := loaded[key]; !ok {
+			if err := r.store.Deregister(ctx, old); err != nil {
+				return err
+			}
+		}
+	}
+	for key, instance := range loaded {
+		if old, ok := r.loaded[key]; ok && reflect.DeepEqual(old, instance) {
+			continue
+		}
+		if err := r.store.Register(ctx, instance); err != nil {
+			return err
+		}
+	}
+	r.loaded = loaded
+	return nil
+}
+
+// Close 停止检查文件
+func (r *Registry) Close() error {
+	r.cancel()
+	return nil
+}
+
+// watchFile 按间隔检查文件修改时间，变更时重新读取，读取失败时保留原实例
+func (r *Registry) watchFile(ctx context.Context, modTime time.Time, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil || info.ModTime().Equal(modTime) {
+				continue
+			}
+			if err := r.Reload(ctx); err != nil {
+				continue
+			}
+			modTime = info.ModTime()
+		}
+	}
+}
+
+// Load 从文件读取服务实例，根据扩展名选择 JSON 或 YAML 格式
+func Load(path string) ([]*registry.ServiceInstance, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Instance
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &items)
+	default:
+		err = json.Unmarshal(data, &items)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("registry: invalid file %s: %v", path, err)
+	}
+
+	instances := make([]*registry.ServiceInstance, 0, len(items))
+	counts := make(map[string]int)
+	for i, item := range items {
+		if item.Name == "" {
+			return nil, fmt.Errorf("registry: instance %d in %s has no name", i, path)
+		}
+		status, err := parseStatus(item.Status)
+		if err != nil {
+			return nil, fmt.Errorf("registry: instance %d in %s: %v", i, path, err)
+		}
+		counts[item.Name]++
+		id := item.ID
+		if id == "" {
+			id = item.Name + "-" + strconv.Itoa(counts[item.Name])
+		}
+		instances = append(instances, &registry.ServiceInstance{
+			ID:        id,
+			Name:      item.Name,
+			Version:   item.Version,
+			Metadata:  item.Metadata,
+			Endpoints: item.Endpoints,
+			Status:    status,
+		})
+	}
+	return instances, nil
+}
+
+// parseStatus 解析服务状态名称
+func parseStatus(s string) (registry.Status, error) {
+	if s == "" {
+		return registry.StatusUp, nil
+	}
+	for _, status := range []registry.Status{registry.StatusUp, registry.StatusStarting, registry.StatusDraining, registry.StatusDown} {
+		if strings.EqualFold(s, status.String()) {
+			return status, nil
+		}
+	}
+	return registry.StatusUp, fmt.Errorf("unknown status %q", s)
+}
@@ -0,0 +1,105 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/registry/registrytest"
+)
+
+func TestRegistry(t *testing.T) {
+	registrytest.Run(t, func(t *testing.T) registry.Registry {
+		path := filepath.Join(t.TempDir(), "services.json")
+		if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		r, err := New(path)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		t.Cleanup(func() { _ = r.Close() })
+		return r
+	})
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`
+- name: svc
+  endpoints: ["grpc://127.0.0.1:9001"]
+- name: svc
+  endpoints: ["grpc://127.0.0.1:9002"]
+  status: draining
+`)
+
+	r, err := New(path, WithInterval(time.Millisecond*10))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	instances, err := r.GetService(ctx, "svc")
+	if err != nil {
+		t.Fatalf("GetService() error = %v", err)
+	}
+	if len(instances) != 2 || instances[0].ID != "svc-1" || instances[1].Status != registry.StatusDraining {
+		t.Fatalf("GetService() = %+v", instances)
+	}
+
+	w, err := r.Watch(ctx, "svc")
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer w.Stop()
+
+	// 确保修改时间变化
+	write(`
+- id: only
+  name: svc
+  endpoints: ["grpc://127.0.0.1:9003"]
+`)
+	mtime := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		instances, err := w.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if len(instances) == 1 && instances[0].ID == "only" {
+			return
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"noname.json": `[{"endpoints": ["127.0.0.1:9000"]}]`,
+		"status.json": `[{"name": "svc", "status": "sleeping"}]`,
+		"syntax.json": `[`,
+	}
+	dir := t.TempDir()
+	for name, data := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%s) error = nil, want error", name)
+		}
+	}
+}
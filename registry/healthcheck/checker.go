@@ -0,0 +1,222 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/huangsc/blade/logger"
+	"github.com/huangsc/blade/registry"
+)
+
+// Options 配置选项
+type Options struct {
+	Interval         time.Duration // 检查间隔
+	Timeout          time.Duration // 单次探测超时时间
+	FailureThreshold int           // 连续失败多少次后标记为 StatusDown
+	SuccessThreshold int           // 连续成功多少次后恢复为 StatusUp
+	Prober           Prober        // 探测器
+	Logger           logger.Logger // 日志记录器，记录状态变更
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithInterval 设置检查间隔
+func WithInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = interval
+	}
+}
+
+// WithTimeout 设置单次探测超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithFailureThreshold 设置连续失败多少次后标记为 StatusDown
+func WithFailureThreshold(n int) Option {
+	return func(o *Options) {
+		o.FailureThreshold = n
+	}
+}
+
+// WithSuccessThreshold 设置连续成功多少次后恢复为 StatusUp
+func WithSuccessThreshold(n int) Option {
+	return func(o *Options) {
+		o.SuccessThreshold = n
+	}
+}
+
+// WithProber 设置探测器
+func WithProber(p Prober) Option {
+	return func(o *Options) {
+		o.Prober = p
+	}
+}
+
+// WithLogger 设置日志记录器
+func WithLogger(l logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// Checker 健康检查器，定期探测注册中心中的服务实例并更新状态
+//
+// 只处理 StatusUp 与 StatusDown 的实例，StatusStarting 与 StatusDraining 的实例由实例自身管理。
+type Checker struct {
+	registry registry.Registry
+	services []string
+	opts     *Options
+	mutex    sync.Mutex
+	states   map[string]*state // 服务名称与实例ID -> 探测状态
+}
+
+// state 实例的连续探测结果
+type state struct {
+	failures  int
+	successes int
+}
+
+// New 创建健康检查器，检查指定服务的所有实例
+func New(r registry.Registry, services []string, opts ...Option) *Checker {
+	options := &Options{
+		Interval:         time.Second * 10,
+		Timeout:          time.Second * 3,
+		FailureThreshold: 3,
+		SuccessThreshold: 1,
+		Prober:           DefaultProber(),
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	return &Checker{
+		registry: r,
+		services: services,
+		opts:     options,
+		states:   make(map[string]*state),
+	}
+}
+
+// Run 按间隔检查直到 ctx 结束
+func (c *Checker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check 并发探测所有实例一次，达到阈值时更新实例状态
+func (c *Checker) Check(ctx context.Context) {
+	seen := make(map[string]struct{})
+	var wg sync.WaitGroup
+	for _, name := range c.services {
+		instances, err := c.registry.GetService(ctx, name)
+		if err != nil {
+			c.logError("healthcheck: get service failed", err, logger.String("service", name))
+			continue
+		}
+		for _, instance := range instances {
+			if instance.Status != registry.StatusUp && instance.Status != registry.StatusDown {
+				continue
+			}
+			seen[instance.Name+"/"+instance.ID] = struct{}{}
+			wg.Add(1)
+			go func(instance *registry.ServiceInstance) {
+				defer wg.Done()
+				c.probe(ctx, instance)
+			}(instance)
+		}
+	}
+	wg.Wait()
+
+	// 清理已不存在的实例
+	c.mutex.Lock()
+	for key := range c.states {
+		if _, ok := seen[key]; !ok {
+			delete(c.states, key)
+		}
+	}
+	c.mutex.Unlock()
+}
+
+// probe 探测实例并在达到阈值时更新状态
+func (c *Checker) probe(ctx context.Context, instance *registry.ServiceInstance) {
+	pctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	err := c.opts.Prober.Probe(pctx, instance)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	key := instance.Name + "/" + instance.ID
+	c.mutex.Lock()
+	st, ok := c.states[key]
+	if !ok {
+		st = &state{}
+		c.states[key] = st
+	}
+	var target registry.Status
+	if err != nil {
+		st.successes = 0
+		st.failures++
+		target = registry.StatusDown
+		if instance.Status == registry.StatusDown || st.failures < c.opts.FailureThreshold {
+			target = instance.Status
+		}
+	} else {
+		st.failures = 0
+		st.successes++
+		target = registry.StatusUp
+		if instance.Status == registry.StatusUp || st.successes < c.opts.SuccessThreshold {
+			target = instance.Status
+		}
+	}
+	c.mutex.Unlock()
+
+	if target == instance.Status {
+		return
+	}
+	// 仅在状态仍为探测前读取的状态时更新，避免覆盖探测期间设置的状态，如下线前的 StatusDraining
+	if serr := c.registry.CompareAndSetStatus(ctx, instance, instance.Status, target); serr != nil {
+		if errors.Is(serr, registry.ErrStatusChanged) || errors.Is(serr, registry.ErrNotFound) {
+			return
+		}
+		c.logError("healthcheck: set status failed", serr,
+			logger.String("service", instance.Name),
+			logger.String("id", instance.ID),
+		)
+		return
+	}
+	if c.opts.Logger != nil {
+		fields := []logger.Field{
+			logger.String("service", instance.Name),
+			logger.String("id", instance.ID),
+			logger.String("status", target.String()),
+		}
+		if err != nil {
+			c.opts.Logger.Warn("healthcheck: instance status changed", append(fields, logger.Error(err))...)
+			return
+		}
+		c.opts.Logger.Info("healthcheck: instance status changed", fields...)
+	}
+}
+
+// logError 输出错误日志
+func (c *Checker) logError(msg string, err error, fields ...logger.Field) {
+	if c.opts.Logger != nil {
+		c.opts.Logger.Error(msg, append(fields, logger.Error(err))...)
+	}
+}
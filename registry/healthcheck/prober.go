@@ -0,0 +1,119 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/huangsc/blade/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ErrNoEndpoint 实例没有探测器支持的地址
+var ErrNoEndpoint = errors.New("healthcheck: no probeable endpoint")
+
+// Prober 探测服务实例是否健康
+type Prober interface {
+	// Probe 探测实例，返回 nil 表示健康
+	Probe(ctx context.Context, instance *registry.ServiceInstance) error
+}
+
+// ProberFunc 函数形式的探测器
+type ProberFunc func(ctx context.Context, instance *registry.ServiceInstance) error
+
+// Probe 实现 Prober 接口
+func (f ProberFunc) Probe(ctx context.Context, instance *registry.ServiceInstance) error {
+	return f(ctx, instance)
+}
+
+// DefaultProber 创建默认探测器，实例有 grpc:// 地址时使用 gRPC 健康检查协议，
+// 否则请求 http:// 或 https:// 地址的 /health 路径
+func DefaultProber() Prober {
+	grpcProber := GRPCProber("")
+	httpProber := HTTPProber("/health", nil)
+	return ProberFunc(func(ctx context.Context, instance *registry.ServiceInstance) error {
+		if _, ok := endpoint(instance, "grpc"); ok {
+			return grpcProber.Probe(ctx, instance)
+		}
+		return httpProber.Probe(ctx, instance)
+	})
+}
+
+// GRPCProber 创建 gRPC 健康检查探测器，请求实例 grpc:// 地址的 grpc.health.v1.Health/Check
+//
+// service 为检查的服务名称，为空时检查服务器整体状态。未设置 dialOpts 时使用非安全连接。
+func GRPCProber(service string, dialOpts ...grpc.DialOption) Prober {
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return ProberFunc(func(ctx context.Context, instance *registry.ServiceInstance) error {
+		u, ok := endpoint(instance, "grpc")
+		if !ok {
+			return ErrNoEndpoint
+		}
+		conn, err := grpc.NewClient(u.Host, dialOpts...)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			return fmt.Errorf("healthcheck: grpc status %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+// HTTPProber 创建 HTTP 探测器，请求实例 http:// 或 https:// 地址的 path，状态码为 2xx 时视为健康
+//
+// client 为 nil 时使用 http.DefaultClient。
+func HTTPProber(path string, client *http.Client) Prober {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return ProberFunc(func(ctx context.Context, instance *registry.ServiceInstance) error {
+		u, ok := endpoint(instance, "http", "https")
+		if !ok {
+			return ErrNoEndpoint
+		}
+		target := url.URL{Scheme: u.Scheme, Host: u.Host, Path: path}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("healthcheck: http status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// endpoint 返回实例中第一个指定协议的地址
+func endpoint(instance *registry.ServiceInstance, schemes ...string) (*url.URL, bool) {
+	for _, e := range instance.Endpoints {
+		u, err := url.Parse(e)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		for _, scheme := range schemes {
+			if u.Scheme == scheme {
+				return u, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/huangsc/blade/registry"
+)
+
+// Registry 内存注册中心，适用于测试与单进程场景
+type Registry struct {
+	mutex    sync.RWMutex
+	services map[string]map[string]*registry.ServiceInstance // 服务名称 -> 实例ID -> 实例
+	watchers map[string]map[*watcher]struct{}                // 服务名称 -> 监听器
+}
+
+// New 创建内存注册中心
+func New() *Registry {
+	return &Registry{
+		services: make(map[string]map[string]*registry.ServiceInstance),
+		watchers: make(map[string]map[*watcher]struct{}),
+	}
+}
+
+// Register 注册服务，相同ID的实例会被替换
+func (r *Registry) Register(ctx context.Context, service *registry.ServiceInstance) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	instances, ok := r.services[service.Name]
+	if !ok {
+		instances = make(map[string]*registry.ServiceInstance)
+		r.services[service.Name] = instances
+	}
+	instances[service.ID] = service
+	r.notify(service.Name)
+	return nil
+}
+
+// Deregister 注销服务
+func (r *Registry) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if instances, ok := r.services[service.Name]; ok {
+		delete(instances, service.ID)
+		if len(instances) == 0 {
+			delete(r.services, service.Name)
+		}
+	}
+	r.notify(service.Name)
+	return nil
+}
+
+// SetStatus 更新已注册实例的状态
+func (r *Registry) SetStatus(ctx context.Context, service *registry.ServiceInstance, status registry.Status) error {
+	return r.setStatus(service, nil, status)
+}
+
+// CompareAndSetStatus 实例当前状态为 old 时更新状态
+func (r *Registry) CompareAndSetStatus(ctx context.Context, service *registry.ServiceInstance, old, status registry.Status) error {
+	return r.setStatus(service, &old, status)
+}
+
+// setStatus 更新实例状态，expected 不为 nil 时要求当前状态与之一致
+func (r *Registry) setStatus(service *registry.ServiceInstance, expected *registry.Status, status registry.Status) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	old, ok := r.services[service.Name][service.ID]
+	if !ok {
+		return registry.ErrNotFound
+	}
+	if expected != nil && old.Status != *expected {
+		return registry.ErrStatusChanged
+	}
+	if old.Status == status {
+		return nil
+	}
+	instance := *old
+	instance.Status = status
+	r.services[service.Name][service.ID] = &instance
+	r.notify(service.Name)
+	return nil
+}
+
+// GetService 获取服务实例列表，按实例ID排序
+func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.instances(serviceName), nil
+}
+
+// Watch 监听服务变更，每次变更后 Next 返回当前的实例列表
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &watcher{
+		registry: r,
+		name:     serviceName,
+		ctx:      ctx,
+		cancel:   cancel,
+		ch:       make(chan struct{}, 1),
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	watchers, ok := r.watchers[serviceName]
+	if !ok {
+		watchers = make(map[*watcher]struct{})
+		r.watchers[serviceName] = watchers
+	}
+	watchers[w] = struct{}{}
+	context.AfterFunc(ctx, func() {
+		r.remove(w)
+	})
+	return w, nil
+}
+
+// instances 返回服务实例列表，调用方需持有锁
+func (r *Registry) instances(serviceName string) []*registry.ServiceInstance {
+	instances := r.services[serviceName]
+	items := make([]*registry.ServiceInstance, 0, len(instances))
+	for _, instance := range instances {
+		items = append(items, instance)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// notify 通知服务的监听器，调用方需持有锁
+func (r *Registry) notify(serviceName string) {
+	for w := range r.watchers[serviceName] {
+		select {
+		case w.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// remove 移除监听器
+func (r *Registry) remove(w *watcher) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if watchers, ok := r.watchers[w.name]; ok {
+		delete(watchers, w)
+		if len(watchers) == 0 {
+			delete(r.watchers, w.name)
+		}
+	}
+}
+
+// watcher 实现了 registry.Watcher 接口
+type watcher struct {
+	registry *Registry
+	name     string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	ch       chan struct{}
+}
+
+// Next 实现 registry.Watcher 接口
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.ch:
+		w.registry.mutex.RLock()
+		defer w.registry.mutex.RUnlock()
+		return w.registry.instances(w.name), nil
+	}
+}
+
+// Stop 实现 registry.Watcher 接口
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.registry.remove(w)
+	return nil
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+	"github.com/huangsc/blade/registry/registrytest"
+)
+
+func TestRegistry(t *testing.T) {
+	registrytest.Run(t, func(t *testing.T) registry.Registry {
+		return New()
+	})
+}
+
+func TestWatchRemovedOnContextCancel(t *testing.T) {
+	r := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := r.Watch(ctx, "svc"); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	cancel()
+
+	// AfterFunc 在独立的 goroutine 中执行
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		r.mutex.RLock()
+		n := len(r.watchers)
+		r.mutex.RUnlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("watcher not removed after context cancel")
+}
@@ -2,9 +2,17 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var (
+	// ErrNotFound 服务实例不存在
+	ErrNotFound = errors.New("registry: service instance not found")
+	// ErrStatusChanged 实例当前状态与预期状态不一致
+	ErrStatusChanged = errors.New("registry: service instance status changed")
+)
+
 // EventType 定义服务事件类型
 type EventType int
 
@@ -17,15 +25,50 @@ const (
 	EventDelete
 )
 
+// Status 定义服务实例状态
+type Status int
+
+const (
+	// StatusUp 正常提供服务，零值为该状态以兼容未设置状态的实例
+	StatusUp Status = iota
+	// StatusStarting 启动中，尚未提供服务
+	StatusStarting
+	// StatusDraining 准备下线，不再接收新请求
+	StatusDraining
+	// StatusDown 不可用，例如健康检查失败
+	StatusDown
+)
+
+// String 返回状态名称
+func (s Status) String() string {
+	switch s {
+	case StatusUp:
+		return "up"
+	case StatusStarting:
+		return "starting"
+	case StatusDraining:
+		return "draining"
+	case StatusDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
 // Registry 定义服务注册与发现接口
 type Registry interface {
 	// Register 注册服务
 	Register(ctx context.Context, service *ServiceInstance) error
 	// Deregister 注销服务
 	Deregister(ctx context.Context, service *ServiceInstance) error
+	// SetStatus 更新已注册实例的状态，实例不存在时返回 ErrNotFound
+	SetStatus(ctx context.Context, service *ServiceInstance, status Status) error
+	// CompareAndSetStatus 实例当前状态为 old 时更新为 status，
+	// 状态不一致时返回 ErrStatusChanged，实例不存在时返回 ErrNotFound
+	CompareAndSetStatus(ctx context.Context, service *ServiceInstance, old, status Status) error
 	// GetService 获取服务实例列表
 	GetService(ctx context.Context, serviceName string) ([]*ServiceInstance, error)
-	// Watch 监听服务变更
+	// Watch 监听服务变更，需要逐个实例的变更事件时使用 WatchEvents
 	Watch(ctx context.Context, serviceName string) (Watcher, error)
 }
 
@@ -36,13 +79,13 @@ type ServiceInstance struct {
 	Version   string            // 服务版本
 	Metadata  map[string]string // 服务元数据
 	Endpoints []string          // 服务地址列表
-	Status    int               // 服务状态
+	Status    Status            // 服务状态，客户端只使用 StatusUp 的实例
 	TTL       time.Duration     // 服务TTL
 }
 
 // Watcher 定义服务监听接口
 type Watcher interface {
-	// Next 返回服务变更事件
+	// Next 阻塞直到服务发生变更，返回变更后完整的实例列表
 	Next() ([]*ServiceInstance, error)
 	// Stop 停止监听
 	Stop() error
@@ -51,6 +94,14 @@ type Watcher interface {
 // Event 定义服务变更事件类型
 type Event struct {
 	Type     EventType
-	Service  *ServiceInstance
-	PreValue *ServiceInstance // 变更前的值
+	Service  *ServiceInstance // 变更后的值，删除事件为被删除的实例
+	PreValue *ServiceInstance // 变更前的值，创建事件为 nil
+}
+
+// EventWatcher 定义服务事件监听接口
+type EventWatcher interface {
+	// Next 返回下一个服务变更事件
+	Next() (*Event, error)
+	// Stop 停止监听
+	Stop() error
 }
@@ -0,0 +1,280 @@
+// Package registrytest 提供 registry.Registry 实现的一致性测试
+package registrytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/huangsc/blade/registry"
+)
+
+// watchTimeout 等待监听结果的超时时间
+const watchTimeout = time.Second * 10
+
+// serial 生成各测试使用的服务名称
+var serial atomic.Int64
+
+// Run 对 registry.Registry 实现运行一致性测试
+//
+// newRegistry 为每个子测试创建注册中心，需要释放的资源通过 t.Cleanup 注册。
+func Run(t *testing.T, newRegistry func(t *testing.T) registry.Registry) {
+	t.Run("Register", func(t *testing.T) { testRegister(t, newRegistry(t)) })
+	t.Run("RegisterReplace", func(t *testing.T) { testRegisterReplace(t, newRegistry(t)) })
+	t.Run("Deregister", func(t *testing.T) { testDeregister(t, newRegistry(t)) })
+	t.Run("GetServiceUnknown", func(t *testing.T) { testGetServiceUnknown(t, newRegistry(t)) })
+	t.Run("SetStatus", func(t *testing.T) { testSetStatus(t, newRegistry(t)) })
+	t.Run("SetStatusNotFound", func(t *testing.T) { testSetStatusNotFound(t, newRegistry(t)) })
+	t.Run("CompareAndSetStatus", func(t *testing.T) { testCompareAndSetStatus(t, newRegistry(t)) })
+	t.Run("WatchSnapshot", func(t *testing.T) { testWatchSnapshot(t, newRegistry(t)) })
+	t.Run("WatchStop", func(t *testing.T) { testWatchStop(t, newRegistry(t)) })
+}
+
+// serviceName 返回未被其他测试使用的服务名称
+func serviceName() string {
+	return fmt.Sprintf("registrytest-%d", serial.Add(1))
+}
+
+// newInstance 创建测试用服务实例
+func newInstance(name, id string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        id,
+		Name:      name,
+		Version:   "v1.0.0",
+		Metadata:  map[string]string{"zone": "a"},
+		Endpoints: []string{"grpc://127.0.0.1:9000", "http://127.0.0.1:8000"},
+	}
+}
+
+func testRegister(t *testing.T, r registry.Registry) {
+	ctx := context.Background()
+	name := serviceName()
+	a, b := newInstance(name, "a"), newInstance(name, "b")
+	// 名称以 name 为前缀的其他服务不应被返回
+	other := newInstance(name+"0", "c")
+
+	for _, instance := range []*registry.ServiceInstance{a, b, other} {
+		if err := r.Register(ctx, instance); err != nil {
+			t.Fatalf("Register(%s) error = %v", instance.ID, err)
+		}
+	}
+	expectService(t, r, name, a, b)
+}
+
+func testRegisterReplace(t *testing.T, r registry.Registry) {
+	ctx := context.Background()
+	name := serviceName()
+	if err := r.Register(ctx, newInstance(name, "a")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	updated := newInstance(name, "a")
+	updated.Version = "v2.0.0"
+	if err := r.Register(ctx, updated); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	expectService(t, r, name, updated)
+}
+
+func testDeregister(t *testing.T, r registry.Registry) {
+	ctx := context.Background()
+	name := serviceName()
+	a, b := newInstance(name, "a"), newInstance(name, "b")
+	for _, instance := range []*registry.ServiceInstance{a, b} {
+		if err := r.Register(ctx, instance); err != nil {
+			t.Fatalf("Register(%s) error = %v", instance.ID, err)
+		}
+	}
+
+	if err := r.Deregister(ctx, a); err != nil {
+		t.Fatalf("Deregister() error = %v", err)
+	}
+	expectService(t, r, name, b)
+
+	if err := r.Deregister(ctx, b); err != nil {
+		t.Fatalf("Deregister() error = %v", err)
+	}
+	expectService(t, r, name)
+}
+
+func testGetServiceUnknown(t *testing.T, r registry.Registry) {
+	expectService(t, r, serviceName())
+}
+
+func testSetStatus(t *testing.T, r registry.Registry) {
+	ctx := context.Background()
+	name := serviceName()
+	a := newInstance(name, "a")
+	if err := r.Register(ctx, a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	for _, status := range []registry.Status{registry.StatusDraining, registry.StatusDraining, registry.StatusUp} {
+		if err := r.SetStatus(ctx, a, status); err != nil {
+			t.Fatalf("SetStatus(%s) error = %v", status, err)
+		}
+		want := *a
+		want.Status = status
+		expectService(t, r, name, &want)
+	}
+}
+
+func testSetStatusNotFound(t *testing.T, r registry.Registry) {
+	err := r.SetStatus(context.Background(), newInstance(serviceName(), "a"), registry.StatusDown)
+	if !errors.Is(err, registry.ErrNotFound) {
+		t.Fatalf("SetStatus() error = %v, want ErrNotFound", err)
+	}
+}
+
+func testCompareAndSetStatus(t *testing.T, r registry.Registry) {
+	ctx := context.Background()
+	name := serviceName()
+	a := newInstance(name, "a")
+	if err := r.Register(ctx, a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := r.CompareAndSetStatus(ctx, a, registry.StatusUp, registry.StatusDown); err != nil {
+		t.Fatalf("CompareAndSetStatus(up, down) error = %v", err)
+	}
+	if err := r.SetStatus(ctx, a, registry.StatusDraining); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	// 状态已被其他调用方修改时不覆盖
+	err := r.CompareAndSetStatus(ctx, a, registry.StatusDown, registry.StatusUp)
+	if !errors.Is(err, registry.ErrStatusChanged) {
+		t.Fatalf("CompareAndSetStatus(down, up) error = %v, want ErrStatusChanged", err)
+	}
+	want := *a
+	want.Status = registry.StatusDraining
+	expectService(t, r, name, &want)
+
+	err = r.CompareAndSetStatus(ctx, newInstance(name, "b"), registry.StatusUp, registry.StatusDown)
+	if !errors.Is(err, registry.ErrNotFound) {
+		t.Fatalf("CompareAndSetStatus() error = %v, want ErrNotFound", err)
+	}
+}
+
+func testWatchSnapshot(t *testing.T, r registry.Registry) {
+	ctx, cancel := context.WithTimeout(context.Background(), watchTimeout)
+	defer cancel()
+	name := serviceName()
+	w, err := r.Watch(ctx, name)
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer w.Stop()
+
+	a, b := newInstance(name, "a"), newInstance(name, "b")
+	if err := r.Register(ctx, a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	expectNext(t, w, a)
+
+	// 每次变更后返回完整的实例列表，而不只是变更的实例
+	if err := r.Register(ctx, b); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	expectNext(t, w, a, b)
+
+	if err := r.SetStatus(ctx, a, registry.StatusDraining); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	draining := *a
+	draining.Status = registry.StatusDraining
+	expectNext(t, w, &draining, b)
+
+	if err := r.Deregister(ctx, a); err != nil {
+		t.Fatalf("Deregister() error = %v", err)
+	}
+	expectNext(t, w, b)
+
+	if err := r.Deregister(ctx, b); err != nil {
+		t.Fatalf("Deregister() error = %v", err)
+	}
+	expectNext(t, w)
+}
+
+func testWatchStop(t *testing.T, r registry.Registry) {
+	ctx, cancel := context.WithTimeout(context.Background(), watchTimeout)
+	defer cancel()
+	w, err := r.Watch(ctx, serviceName())
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Next()
+		done <- err
+	}()
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Next() after Stop() error = nil, want error")
+		}
+	case <-time.After(watchTimeout):
+		t.Fatal("Next() did not return after Stop()")
+	}
+}
+
+// expectService 检查 GetService 返回的实例列表
+func expectService(t *testing.T, r registry.Registry, name string, want ...*registry.ServiceInstance) {
+	t.Helper()
+	got, err := r.GetService(context.Background(), name)
+	if err != nil {
+		t.Fatalf("GetService(%s) error = %v", name, err)
+	}
+	if !equal(got, want) {
+		t.Fatalf("GetService(%s) = %s, want %s", name, format(got), format(want))
+	}
+}
+
+// expectNext 检查 Watcher.Next 返回的实例列表
+func expectNext(t *testing.T, w registry.Watcher, want ...*registry.ServiceInstance) {
+	t.Helper()
+	got, err := w.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if !equal(got, want) {
+		t.Fatalf("Next() = %s, want %s", format(got), format(want))
+	}
+}
+
+// equal 忽略顺序比较实例列表
+func equal(got, want []*registry.ServiceInstance) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	got, want = sorted(got), sorted(want)
+	for i := range got {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sorted 返回按实例ID排序的副本
+func sorted(instances []*registry.ServiceInstance) []*registry.ServiceInstance {
+	items := append([]*registry.ServiceInstance(nil), instances...)
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// format 格式化实例列表用于错误信息
+func format(instances []*registry.ServiceInstance) string {
+	items := make([]string, 0, len(instances))
+	for _, instance := range sorted(instances) {
+		items = append(items, fmt.Sprintf("%+v", *instance))
+	}
+	return fmt.Sprint(items)
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"sort"
+)
+
+// String 返回事件类型名称
+func (t EventType) String() string {
+	switch t {
+	case EventCreate:
+		return "create"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Diff 比较变更前后的实例列表，按实例ID返回创建、更新与删除事件
+func Diff(prev, next []*ServiceInstance) []Event {
+	before := make(map[string]*ServiceInstance, len(prev))
+	for _, instance := range prev {
+		before[instance.ID] = instance
+	}
+
+	var events []Event
+	after := make(map[string]struct{}, len(next))
+	for _, instance := range next {
+		after[instance.ID] = struct{}{}
+		old, ok := before[instance.ID]
+		switch {
+		case !ok:
+			events = append(events, Event{Type: EventCreate, Service: instance})
+		case !reflect.DeepEqual(old, instance):
+			events = append(events, Event{Type: EventUpdate, Service: instance, PreValue: old})
+		}
+	}
+	for _, instance := range prev {
+		if _, ok := after[instance.ID]; !ok {
+			events = append(events, Event{Type: EventDelete, Service: instance, PreValue: instance})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Service.ID < events[j].Service.ID })
+	return events
+}
+
+// WatchEvents 监听服务变更事件
+//
+// 首先以 GetService 返回的实例列表为初始状态，为每个实例产生 EventCreate 事件，
+// 之后比较 Watcher 每次返回的完整实例列表产生事件。
+func WatchEvents(ctx context.Context, r Registry, serviceName string) (EventWatcher, error) {
+	w, err := r.Watch(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := r.GetService(ctx, serviceName)
+	if err != nil {
+		_ = w.Stop()
+		return nil, err
+	}
+	return &eventWatcher{
+		watcher:   w,
+		instances: instances,
+		pending:   Diff(nil, instances),
+	}, nil
+}
+
+// eventWatcher 实现了 EventWatcher 接口
+type eventWatcher struct {
+	watcher   Watcher
+	instances []*ServiceInstance
+	pending   []Event
+}
+
+// Next 实现 EventWatcher 接口
+func (w *eventWatcher) Next() (*Event, error) {
+	for len(w.pending) == 0 {
+		instances, err := w.watcher.Next()
+		if err != nil {
+			return nil, err
+		}
+		w.pending = Diff(w.instances, instances)
+		w.instances = instances
+	}
+	event := w.pending[0]
+	w.pending = w.pending[1:]
+	return &event, nil
+}
+
+// Stop 实现 EventWatcher 接口
+func (w *eventWatcher) Stop() error {
+	return w.watcher.Stop()
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 将通用中间件转换为一元服务端拦截器
+//
+// 上下文中尚无服务端传输信息时会写入 transport.KindGRPC 的传输信息，
+// 中间件设置的响应头在处理完成后作为响应元数据发送。
+func UnaryServerInterceptor(m ...middleware.Middleware) grpc.UnaryServerInterceptor {
+	chain := middleware.Chain(m...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tr, ok := transport.FromServerContext(ctx)
+		if !ok {
+			tr = newTransport(ctx, info.FullMethod)
+			ctx = transport.NewServerContext(ctx, tr)
+		}
+
+		resp, err := chain(middleware.Handler(handler))(ctx, req)
+		if t, ok := tr.(*grpcTransport); ok && len(t.replyHeader) > 0 {
+			_ = grpc.SetHeader(ctx, metadata.MD(t.replyHeader))
+		}
+		return resp, toStatus(err)
+	}
+}
+
+// StreamServerInterceptor 将通用中间件转换为流式服务端拦截器
+//
+// 中间件在整个流的生命周期内执行一次，收到的请求为 grpc.ServerStream。
+// 中间件设置的响应头在调用处理函数前作为响应元数据发送。
+func StreamServerInterceptor(m ...middleware.Middleware) grpc.StreamServerInterceptor {
+	chain := middleware.Chain(m...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		tr, ok := transport.FromServerContext(ctx)
+		if !ok {
+			tr = newTransport(ctx, info.FullMethod)
+			ctx = transport.NewServerContext(ctx, tr)
+		}
+
+		next := func(ctx context.Context, req interface{}) (interface{}, error) {
+			if t, ok := tr.(*grpcTransport); ok && len(t.replyHeader) > 0 {
+				if err := ss.SetHeader(metadata.MD(t.replyHeader)); err != nil {
+					return nil, err
+				}
+			}
+			return nil, handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+		}
+		_, err := chain(next)(ctx, ss)
+		return toStatus(err)
+	}
+}
+
+// toStatus 将 panic 错误转换为 Internal 状态，超时错误转换为 DeadlineExceeded 状态
+//
+// panic 的值与调用栈不返回给客户端，由 middleware.Recovery 记录日志。
+func toStatus(err error) error {
+	var pe *middleware.PanicError
+	if errors.As(err, &pe) {
+		return status.Error(codes.Internal, "internal error")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return err
+}
+
+// wrappedServerStream 替换上下文的服务端流
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 实现 grpc.ServerStream 接口
+func (w *wrappedServerStream) Context() context.Context {
+	return w.ctx
+}
+
+// grpcTransport gRPC服务端传输信息
+type grpcTransport struct {
+	endpoint    string
+	operation   string
+	reqHeader   transport.MetadataCarrier
+	replyHeader transport.MetadataCarrier
+}
+
+// newTransport 从入站上下文创建传输信息
+func newTransport(ctx context.Context, method string) *grpcTransport {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if md == nil {
+		md = metadata.MD{}
+	}
+
+	var endpoint string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		endpoint = p.Addr.String()
+	}
+
+	return &grpcTransport{
+		endpoint:    endpoint,
+		operation:   method,
+		reqHeader:   transport.MetadataCarrier(md),
+		replyHeader: transport.MetadataCarrier{},
+	}
+}
+
+// Kind 实现 transport.Transporter 接口
+func (t *grpcTransport) Kind() transport.Kind {
+	return transport.KindGRPC
+}
+
+// Endpoint 实现 transport.Transporter 接口
+func (t *grpcTransport) Endpoint() string {
+	return t.endpoint
+}
+
+// Operation 实现 transport.Transporter 接口
+func (t *grpcTransport) Operation() string {
+	return t.operation
+}
+
+// RequestHeader 实现 transport.Transporter 接口
+func (t *grpcTransport) RequestHeader() transport.Header {
+	return t.reqHeader
+}
+
+// ReplyHeader 实现 transport.Transporter 接口
+func (t *grpcTransport) ReplyHeader() transport.Header {
+	return t.replyHeader
+}
@@ -2,11 +2,15 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"time"
 
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
@@ -25,11 +29,15 @@ type Server struct {
 type Options struct {
 	Address            string                         // 服务地址
 	Port               int                            // 服务端口
-	Timeout            time.Duration                  // 超时时间
+	Timeout            time.Duration                  // 一元调用的处理超时时间，为 0 时不设置
+	MethodTimeouts     map[string]time.Duration       // 按方法配置的处理超时时间
+	MaxTimeout         time.Duration                  // 处理超时时间与调用方截止时间的上限，为 0 时不限制
 	EnableHealth       bool                           // 是否启用健康检查
 	EnableReflect      bool                           // 是否启用反射服务
+	Middleware         []middleware.Middleware        // 中间件列表
 	UnaryInterceptors  []grpc.UnaryServerInterceptor  // 一元拦截器
 	StreamInterceptors []grpc.StreamServerInterceptor // 流式拦截器
+	TLSConfig          *tls.Config                    // TLS配置，为 nil 时不启用TLS
 }
 
 // Option 定义配置函数类型
@@ -56,6 +64,23 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithMethodTimeout 设置指定方法的处理超时时间，method 为完整方法名
+func WithMethodTimeout(method string, timeout time.Duration) Option {
+	return func(o *Options) {
+		if o.MethodTimeouts == nil {
+			o.MethodTimeouts = make(map[string]time.Duration)
+		}
+		o.MethodTimeouts[method] = timeout
+	}
+}
+
+// WithMaxTimeout 设置处理超时时间与调用方截止时间的上限
+func WithMaxTimeout(max time.Duration) Option {
+	return func(o *Options) {
+		o.MaxTimeout = max
+	}
+}
+
 // WithHealth 设置是否启用健康检查
 func WithHealth(enable bool) Option {
 	return func(o *Options) {
@@ -70,6 +95,13 @@ func WithReflection(enable bool) Option {
 	}
 }
 
+// WithMiddleware 添加中间件，中间件在拦截器之前执行
+func WithMiddleware(m ...middleware.Middleware) Option {
+	return func(o *Options) {
+		o.Middleware = append(o.Middleware, m...)
+	}
+}
+
 // WithUnaryInterceptors 添加一元拦截器
 func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
 	return func(o *Options) {
@@ -84,6 +116,13 @@ func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option
 	}
 }
 
+// WithTLSConfig 设置TLS配置，可使用 tlsconfig.NewServerConfig 创建
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
 // New 创建gRPC服务器
 func New(opts ...Option) *Server {
 	options := &Options{
@@ -98,13 +137,24 @@ func New(opts ...Option) *Server {
 
 	var serverOpts []grpc.ServerOption
 
-	// 添加拦截器
-	if len(options.UnaryInterceptors) > 0 {
-		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(options.UnaryInterceptors...))
+	// 设置TLS
+	if options.TLSConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(options.TLSConfig)))
 	}
-	if len(options.StreamInterceptors) > 0 {
-		serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(options.StreamInterceptors...))
+
+	// 添加拦截器，中间件拦截器始终位于最前，未配置中间件时仍会写入传输信息
+	// 超时中间件仅作用于一元调用，避免中断长连接的流式调用
+	timeoutOpts := []middleware.TimeoutOption{middleware.WithMaxTimeout(options.MaxTimeout)}
+	for method, timeout := range options.MethodTimeouts {
+		timeoutOpts = append(timeoutOpts, middleware.WithOperationTimeout(method, timeout))
 	}
+	unaryMiddleware := append([]middleware.Middleware{middleware.Timeout(options.Timeout, timeoutOpts...)}, options.Middleware...)
+	unaryInterceptors := append([]grpc.UnaryServerInterceptor{UnaryServerInterceptor(unaryMiddleware...)}, options.UnaryInterceptors...)
+	streamInterceptors := append([]grpc.StreamServerInterceptor{StreamServerInterceptor(options.Middleware...)}, options.StreamInterceptors...)
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
 
 	// 添加keepalive策略
 	serverOpts = append(serverOpts, grpc.KeepaliveParams(keepalive.ServerParameters{
@@ -159,6 +209,27 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
 	}
 
-	s.GracefulStop()
-	return nil
+	// 优雅停止等待进行中的调用结束，ctx 结束时强制关闭连接
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Server.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// Endpoint 返回服务访问地址
+func (s *Server) Endpoint() (string, error) {
+	addr, err := server.ExtractHostPort(s.opts.Address, s.opts.Port)
+	if err != nil {
+		return "", err
+	}
+	return "grpc://" + addr, nil
 }
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ExtractHostPort 根据监听地址和端口生成可被外部访问的 host:port
+//
+// 当监听地址为空或为通配地址(0.0.0.0、::)时，会选择本机第一个
+// 非回环的 IPv4 地址作为对外地址。
+func ExtractHostPort(address string, port int) (string, error) {
+	host := address
+	if host == "" || host == "0.0.0.0" || host == "::" || host == "[::]" {
+		ip, err := privateIP()
+		if err != nil {
+			return "", err
+		}
+		host = ip
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// privateIP 获取本机第一个非回环的 IPv4 地址
+func privateIP() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip.String(), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("server: no available host ip")
+}
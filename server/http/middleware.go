@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/transport"
+)
+
+// Middleware 将通用中间件转换为 gin 中间件
+//
+// 中间件收到的请求为 *http.Request，中间件修改的上下文会传递给后续处理函数。
+// 上下文中尚无服务端传输信息时会写入 transport.KindHTTP 的传输信息。
+// 中间件返回错误且尚未写入响应时返回错误响应，超时错误返回 504，panic 只返回通用的内部错误；
+// 中间件未调用后续处理函数时终止请求，返回值不为 nil 则以 JSON 写入响应。
+func Middleware(m ...middleware.Middleware) gin.HandlerFunc {
+	chain := middleware.Chain(m...)
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if _, ok := transport.FromServerContext(ctx); !ok {
+			ctx = transport.NewServerContext(ctx, newTransport(c))
+		}
+
+		called := false
+		next := func(ctx context.Context, req interface{}) (interface{}, error) {
+			called = true
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			if err := c.Errors.Last(); err != nil {
+				return nil, err.Err
+			}
+			return nil, nil
+		}
+
+		resp, err := chain(next)(ctx, c.Request)
+		if err != nil {
+			if !c.Writer.Written() {
+				c.AbortWithStatusJSON(errorStatus(err), gin.H{
+					"error": errorMessage(err),
+				})
+				return
+			}
+			c.Abort()
+			return
+		}
+		if !called {
+			if resp != nil && !c.Writer.Written() {
+				c.JSON(http.StatusOK, resp)
+			}
+			c.Abort()
+		}
+	}
+}
+
+// errorMessage 返回写入响应的错误信息，panic 只返回通用错误，详细信息由 middleware.Recovery 记录
+func errorMessage(err error) string {
+	var pe *middleware.PanicError
+	if errors.As(err, &pe) {
+		return "internal error"
+	}
+	return err.Error()
+}
+
+// errorStatus 返回错误对应的HTTP状态码，超时返回 504
+func errorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// httpTransport HTTP服务端传输信息
+type httpTransport struct {
+	endpoint    string
+	operation   string
+	reqHeader   transport.HeaderCarrier
+	replyHeader transport.HeaderCarrier
+}
+
+// newTransport 从 gin 上下文创建传输信息
+//
+// 操作名称为路由模板，未匹配路由时为请求路径。
+func newTransport(c *gin.Context) *httpTransport {
+	operation := c.FullPath()
+	if operation == "" {
+		operation = c.Request.URL.Path
+	}
+	return &httpTransport{
+		endpoint:    c.Request.RemoteAddr,
+		operation:   operation,
+		reqHeader:   transport.HeaderCarrier(c.Request.Header),
+		replyHeader: transport.HeaderCarrier(c.Writer.Header()),
+	}
+}
+
+// Kind 实现 transport.Transporter 接口
+func (t *httpTransport) Kind() transport.Kind {
+	return transport.KindHTTP
+}
+
+// Endpoint 实现 transport.Transporter 接口
+func (t *httpTransport) Endpoint() string {
+	return t.endpoint
+}
+
+// Operation 实现 transport.Transporter 接口
+func (t *httpTransport) Operation() string {
+	return t.operation
+}
+
+// RequestHeader 实现 transport.Transporter 接口
+func (t *httpTransport) RequestHeader() transport.Header {
+	return t.reqHeader
+}
+
+// ReplyHeader 实现 transport.Transporter 接口
+func (t *httpTransport) ReplyHeader() transport.Header {
+	return t.replyHeader
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/huangsc/blade/metrics"
+	"github.com/huangsc/blade/middleware"
+	"github.com/huangsc/blade/server"
 )
 
 // Server HTTP服务器
@@ -18,12 +21,16 @@ type Server struct {
 
 // Options HTTP服务器配置选项
 type Options struct {
-	Address    string            // 服务地址
-	Port       int               // 服务端口
-	Timeout    time.Duration     // 超时时间
-	Mode       string            // 运行模式
-	Middleware []gin.HandlerFunc // 中间件列表
-	Headers    map[string]string // 全局响应头
+	Address          string                   // 服务地址
+	Port             int                      // 服务端口
+	Timeout          time.Duration            // 读写超时时间，同时作为请求处理超时时间
+	RouteTimeouts    map[string]time.Duration // 按路由配置的处理超时时间
+	MaxTimeout       time.Duration            // 处理超时时间与调用方截止时间的上限，为 0 时不限制
+	Mode             string                   // 运行模式
+	Middleware       []gin.HandlerFunc        // gin 中间件列表
+	ServerMiddleware []middleware.Middleware  // 通用中间件列表，在 gin 中间件之前执行
+	Headers          map[string]string        // 全局响应头
+	MetricsPath      string                   // 监控指标路径，设置后在该路径提供 Prometheus 指标
 }
 
 // Option 定义配置函数类型
@@ -50,6 +57,23 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithRouteTimeout 设置指定路由的处理超时时间，route 为路由模板，如 /users/:id
+func WithRouteTimeout(route string, timeout time.Duration) Option {
+	return func(o *Options) {
+		if o.RouteTimeouts == nil {
+			o.RouteTimeouts = make(map[string]time.Duration)
+		}
+		o.RouteTimeouts[route] = timeout
+	}
+}
+
+// WithMaxTimeout 设置处理超时时间与调用方截止时间的上限
+func WithMaxTimeout(max time.Duration) Option {
+	return func(o *Options) {
+		o.MaxTimeout = max
+	}
+}
+
 // WithMode 设置运行模式
 func WithMode(mode string) Option {
 	return func(o *Options) {
@@ -57,10 +81,17 @@ func WithMode(mode string) Option {
 	}
 }
 
-// WithMiddleware 添加中间件
-func WithMiddleware(middleware ...gin.HandlerFunc) Option {
+// WithMiddleware 添加 gin 中间件
+func WithMiddleware(handlers ...gin.HandlerFunc) Option {
+	return func(o *Options) {
+		o.Middleware = append(o.Middleware, handlers...)
+	}
+}
+
+// WithServerMiddleware 添加通用中间件，与 gRPC 服务器共用同一套中间件
+func WithServerMiddleware(m ...middleware.Middleware) Option {
 	return func(o *Options) {
-		o.Middleware = append(o.Middleware, middleware...)
+		o.ServerMiddleware = append(o.ServerMiddleware, m...)
 	}
 }
 
@@ -71,6 +102,13 @@ func WithHeaders(headers map[string]string) Option {
 	}
 }
 
+// WithMetricsPath 设置监控指标路径，在该路径挂载 Prometheus 指标处理器
+func WithMetricsPath(path string) Option {
+	return func(o *Options) {
+		o.MetricsPath = path
+	}
+}
+
 // New 创建HTTP服务器
 func New(opts ...Option) *Server {
 	options := &Options{
@@ -89,7 +127,12 @@ func New(opts ...Option) *Server {
 	// 添加基础中间件
 	engine.Use(gin.Recovery())
 
-	// 添加自定义中间件
+	// 添加自定义中间件，超时中间件位于最前，未配置中间件时仍会向上下文写入传输信息
+	timeoutOpts := []middleware.TimeoutOption{middleware.WithMaxTimeout(options.MaxTimeout)}
+	for route, timeout := range options.RouteTimeouts {
+		timeoutOpts = append(timeoutOpts, middleware.WithOperationTimeout(route, timeout))
+	}
+	engine.Use(Middleware(append([]middleware.Middleware{middleware.Timeout(options.Timeout, timeoutOpts...)}, options.ServerMiddleware...)...))
 	if len(options.Middleware) > 0 {
 		engine.Use(options.Middleware...)
 	}
@@ -104,30 +147,40 @@ func New(opts ...Option) *Server {
 		})
 	}
 
+	// 挂载监控指标
+	if options.MetricsPath != "" {
+		engine.GET(options.MetricsPath, gin.WrapH(metrics.Handler()))
+	}
+
+	// 在创建时初始化 http.Server，Stop 先于 Start 执行时 Start 直接返回 http.ErrServerClosed
 	return &Server{
 		Engine: engine,
 		opts:   options,
+		server: &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", options.Address, options.Port),
+			Handler:      engine,
+			ReadTimeout:  options.Timeout,
+			WriteTimeout: options.Timeout,
+			IdleTimeout:  options.Timeout * 2,
+		},
 	}
 }
 
 // Start 启动服务器
 func (s *Server) Start() error {
-	addr := fmt.Sprintf("%s:%d", s.opts.Address, s.opts.Port)
-	s.server = &http.Server{
-		Addr:         addr,
-		Handler:      s.Engine,
-		ReadTimeout:  s.opts.Timeout,
-		WriteTimeout: s.opts.Timeout,
-		IdleTimeout:  s.opts.Timeout * 2,
-	}
-
 	return s.server.ListenAndServe()
 }
 
 // Stop 停止服务器
 func (s *Server) Stop(ctx context.Context) error {
-	if s.server != nil {
-		return s.server.Shutdown(ctx)
+	return s.server.Shutdown(ctx)
+}
+
+// Endpoint 返回服务访问地址
+func (s *Server) Endpoint() (string, error) {
+	addr, err := server.ExtractHostPort(s.opts.Address, s.opts.Port)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	return "http://" + addr, nil
 }
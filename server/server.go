@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+
+	"github.com/huangsc/blade/middleware"
 )
 
 // Server 接口定义了服务器的基本行为
@@ -12,13 +14,22 @@ type Server interface {
 	Stop(context.Context) error
 }
 
+// Endpointer 定义可暴露访问地址的服务器
+//
+// 实现该接口的服务器会在应用启动后将地址注册到注册中心，
+// 地址格式为 scheme://host:port，例如 grpc://10.0.0.1:9000。
+type Endpointer interface {
+	// Endpoint 返回服务访问地址
+	Endpoint() (string, error)
+}
+
 // Options 定义服务器配置选项
 type Options struct {
-	Address     string            // 服务地址
-	Port        int               // 服务端口
-	Middleware  []interface{}     // 中间件列表
-	MetricsPath string            // 监控指标路径
-	Headers     map[string]string // 全局响应头
+	Address     string                  // 服务地址
+	Port        int                     // 服务端口
+	Middleware  []middleware.Middleware // 中间件列表
+	MetricsPath string                  // 监控指标路径
+	Headers     map[string]string       // 全局响应头
 }
 
 // Option 定义配置函数类型
@@ -39,9 +50,9 @@ func WithPort(port int) Option {
 }
 
 // WithMiddleware 添加中间件
-func WithMiddleware(middleware ...interface{}) Option {
+func WithMiddleware(m ...middleware.Middleware) Option {
 	return func(o *Options) {
-		o.Middleware = append(o.Middleware, middleware...)
+		o.Middleware = append(o.Middleware, m...)
 	}
 }
 
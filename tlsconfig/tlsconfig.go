@@ -0,0 +1,275 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoCertificate 未配置证书
+	ErrNoCertificate = errors.New("tlsconfig: no certificate configured")
+	// ErrInvalidCA 无效的CA证书
+	ErrInvalidCA = errors.New("tlsconfig: no valid certificates in CA file")
+)
+
+// Options TLS配置选项
+type Options struct {
+	CertFile       string             // 证书文件
+	KeyFile        string             // 私钥文件
+	CAFile         string             // CA证书文件，服务端用于验证客户端证书，客户端用于验证服务端证书
+	ClientAuth     tls.ClientAuthType // 客户端认证模式，仅服务端使用
+	ServerName     string             // 服务端名称，仅客户端使用
+	MinVersion     uint16             // 最低TLS版本
+	ReloadInterval time.Duration      // 检查证书文件变更的最小间隔，为0时不重新加载
+}
+
+// Option 定义配置函数类型
+type Option func(*Options)
+
+// WithCertificate 设置证书和私钥文件
+func WithCertificate(certFile, keyFile string) Option {
+	return func(o *Options) {
+		o.CertFile = certFile
+		o.KeyFile = keyFile
+	}
+}
+
+// WithCA 设置CA证书文件
+func WithCA(caFile string) Option {
+	return func(o *Options) {
+		o.CAFile = caFile
+	}
+}
+
+// WithClientAuth 设置客户端认证模式
+func WithClientAuth(auth tls.ClientAuthType) Option {
+	return func(o *Options) {
+		o.ClientAuth = auth
+	}
+}
+
+// WithServerName 设置服务端名称
+func WithServerName(name string) Option {
+	return func(o *Options) {
+		o.ServerName = name
+	}
+}
+
+// WithMinVersion 设置最低TLS版本
+func WithMinVersion(version uint16) Option {
+	return func(o *Options) {
+		o.MinVersion = version
+	}
+}
+
+// WithReloadInterval 设置检查证书文件变更的最小间隔
+func WithReloadInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.ReloadInterval = interval
+	}
+}
+
+// NewServerConfig 创建服务端TLS配置
+//
+// 启用重新加载时，每次握手会按 ReloadInterval 检查证书和CA文件的修改时间，
+// 文件变更后新连接使用新的证书，加载失败时保留原证书。
+func NewServerConfig(opts ...Option) (*tls.Config, error) {
+	options := newOptions(opts...)
+	if options.CertFile == "" || options.KeyFile == "" {
+		return nil, ErrNoCertificate
+	}
+
+	r, err := newReloader(options)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth := options.ClientAuth
+	if clientAuth == tls.NoClientCert && options.CAFile != "" {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	base := &tls.Config{
+		MinVersion: options.MinVersion,
+		ClientAuth: clientAuth,
+	}
+	return &tls.Config{
+		MinVersion: options.MinVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.load()
+			cfg := base.Clone()
+			cfg.Certificates = []tls.Certificate{*cert}
+			cfg.ClientCAs = pool
+			return cfg, nil
+		},
+	}, nil
+}
+
+// NewClientConfig 创建客户端TLS配置
+//
+// 配置证书时在服务端要求时提供客户端证书，配置CA时使用该CA验证服务端证书，
+// 否则使用系统根证书。
+func NewClientConfig(opts ...Option) (*tls.Config, error) {
+	options := newOptions(opts...)
+
+	r, err := newReloader(options)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: options.MinVersion,
+		ServerName: options.ServerName,
+	}
+	if options.CertFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.load()
+			return cert, nil
+		}
+	}
+	if options.CAFile != "" {
+		// 使用当前CA验证服务端证书，以便CA文件变更后无需重建连接配置
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, pool := r.load()
+			return verifyServer(cs, pool)
+		}
+	}
+	return cfg, nil
+}
+
+// newOptions 创建TLS配置选项
+func newOptions(opts ...Option) *Options {
+	options := &Options{
+		MinVersion: tls.VersionTLS12,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return options
+}
+
+// verifyServer 使用CA验证服务端证书链和名称
+func verifyServer(cs tls.ConnectionState, pool *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tlsconfig: server presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         pool,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// reloader 按修改时间重新加载证书文件
+type reloader struct {
+	opts *Options
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+// newReloader 创建证书加载器并完成首次加载
+func newReloader(opts *Options) (*reloader, error) {
+	r := &reloader{
+		opts:     opts,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	r.checkedAt = time.Now()
+	return r, nil
+}
+
+// load 返回当前证书和CA，到达检查间隔且文件变更时重新加载
+func (r *reloader) load() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.opts.ReloadInterval > 0 && time.Since(r.checkedAt) >= r.opts.ReloadInterval {
+		r.checkedAt = time.Now()
+		if r.changed() {
+			// 加载失败时保留原证书，文件可能正在写入
+			_ = r.reload()
+		}
+	}
+	return r.cert, r.pool
+}
+
+// changed 检查证书文件是否变更
+func (r *reloader) changed() bool {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload 加载证书文件
+func (r *reloader) reload() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	var cert *tls.Certificate
+	if r.opts.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("tlsconfig: load certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.opts.CAFile != "" {
+		data, err := os.ReadFile(r.opts.CAFile)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return ErrInvalidCA
+		}
+	}
+
+	r.cert = cert
+	r.pool = pool
+	r.modTimes = modTimes
+	return nil
+}
+
+// files 返回需要监听的文件
+func (r *reloader) files() []string {
+	var files []string
+	for _, f := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.CAFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
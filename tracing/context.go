@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanFromContext 返回上下文中的当前 Span，上下文中没有 Span 时返回不记录的 Span
+func SpanFromContext(ctx context.Context) Span {
+	return &otelSpan{span: trace.SpanFromContext(ctx)}
+}
+
+// SpanContextFromContext 返回上下文中的 Span 上下文，可用于 WithLinks 链接 Span
+func SpanContextFromContext(ctx context.Context) trace.SpanContext {
+	return trace.SpanContextFromContext(ctx)
+}
+
+// TraceIDFromContext 返回上下文中的 TraceID，没有有效 Span 时返回空字符串
+func TraceIDFromContext(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// SpanIDFromContext 返回上下文中的 SpanID，没有有效 Span 时返回空字符串
+func SpanIDFromContext(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() {
+		return sc.SpanID().String()
+	}
+	return ""
+}
+
+// SetBaggage 返回设置了 baggage 成员的上下文，baggage 随追踪上下文传播到下游
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, err
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, err
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// BaggageFromContext 返回上下文中 baggage 成员的值，不存在时返回空字符串
+func BaggageFromContext(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
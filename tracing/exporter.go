@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/credentials"
+)
+
+// ExporterKind 导出器类型
+type ExporterKind string
+
+const (
+	// ExporterOTLPGRPC 通过 OTLP/gRPC 导出，默认端点为 localhost:4317
+	ExporterOTLPGRPC ExporterKind = "otlp-grpc"
+	// ExporterOTLPHTTP 通过 OTLP/HTTP 导出，默认端点为 localhost:4318
+	ExporterOTLPHTTP ExporterKind = "otlp-http"
+	// ExporterStdout 以 JSON 输出到标准输出
+	ExporterStdout ExporterKind = "stdout"
+	// ExporterFile 以 JSON 输出到 FilePath 指定的文件
+	ExporterFile ExporterKind = "file"
+	// ExporterNoop 丢弃所有 Span
+	ExporterNoop ExporterKind = "noop"
+)
+
+// NewMemoryExporter 创建内存导出器，用于在测试中检查导出的 Span
+//
+// 通常配合 WithSyncExport 使用，关闭追踪器时会清空已导出的 Span。
+func NewMemoryExporter() *tracetest.InMemoryExporter {
+	return tracetest.NewInMemoryExporter()
+}
+
+// newExporter 根据配置创建导出器
+func newExporter(ctx context.Context, o *Options) (sdktrace.SpanExporter, error) {
+	if o.SpanExporter != nil {
+		return o.SpanExporter, nil
+	}
+
+	switch o.Exporter {
+	case "", ExporterOTLPGRPC:
+		return newOTLPGRPCExporter(ctx, o)
+	case ExporterOTLPHTTP:
+		return newOTLPHTTPExporter(ctx, o)
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterFile:
+		f, err := os.OpenFile(o.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &fileExporter{SpanExporter: exp, file: f}, nil
+	case ExporterNoop:
+		return noopExporter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported exporter: %s", o.Exporter)
+	}
+}
+
+// newOTLPGRPCExporter 创建 OTLP/gRPC 导出器，连接在后台建立，不阻塞启动
+func newOTLPGRPCExporter(ctx context.Context, o *Options) (sdktrace.SpanExporter, error) {
+	endpoint := o.Endpoint
+	if endpoint == "" {
+		endpoint = "localhost:4317"
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithTimeout(o.Timeout),
+		otlptracegrpc.WithRetry(o.retryConfig()),
+	}
+	if o.TLSConfig != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(o.TLSConfig)))
+	} else {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(o.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(o.Headers))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newOTLPHTTPExporter 创建 OTLP/HTTP 导出器
+func newOTLPHTTPExporter(ctx context.Context, o *Options) (sdktrace.SpanExporter, error) {
+	endpoint := o.Endpoint
+	if endpoint == "" {
+		endpoint = "localhost:4318"
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithTimeout(o.Timeout),
+		otlptracehttp.WithRetry(otlptracehttp.RetryConfig(o.retryConfig())),
+	}
+	if o.TLSConfig != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(o.TLSConfig))
+	} else {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(o.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(o.Headers))
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
+
+// retryConfig 返回导出失败时的重试配置
+//
+// 重试间隔从 RetryDelay 开始指数增长，最多 RetryDelay 的两倍，
+// 总时长按 RetryCount 次重试估算，RetryCount 为 0 时不重试。
+func (o *Options) retryConfig() otlptracegrpc.RetryConfig {
+	if o.RetryCount <= 0 {
+		return otlptracegrpc.RetryConfig{Enabled: false}
+	}
+	return otlptracegrpc.RetryConfig{
+		Enabled:         true,
+		InitialInterval: o.RetryDelay,
+		MaxInterval:     o.RetryDelay * 2,
+		MaxElapsedTime:  o.RetryDelay * 2 * time.Duration(o.RetryCount),
+	}
+}
+
+// fileExporter 输出到文件的导出器，关闭时关闭文件
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+// Shutdown 实现 sdktrace.SpanExporter 接口
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if cerr := e.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// noopExporter 丢弃所有 Span 的导出器
+type noopExporter struct{}
+
+// ExportSpans 实现 sdktrace.SpanExporter 接口
+func (noopExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+// Shutdown 实现 sdktrace.SpanExporter 接口
+func (noopExporter) Shutdown(context.Context) error {
+	return nil
+}
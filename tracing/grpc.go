@@ -0,0 +1,227 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/huangsc/blade/transport"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 创建一元 RPC 服务端追踪拦截器
+//
+// 从入站元数据中提取 W3C 追踪上下文并创建服务端 Span，Span 名称为不带前导斜杠的完整方法名。
+func UnaryServerInterceptor(t Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, t, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		endServerSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 创建流式 RPC 服务端追踪拦截器，Span 覆盖整个流的生命周期
+func StreamServerInterceptor(t Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), t, info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+		endServerSpan(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 创建一元 RPC 客户端追踪拦截器
+//
+// 创建客户端 Span 并将 W3C 追踪上下文注入出站元数据。
+func UnaryClientInterceptor(t Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startClientSpan(ctx, t, cc.Target(), method)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endClientSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor 创建流式 RPC 客户端追踪拦截器
+//
+// Span 在接收消息返回错误或 io.EOF 时结束，服务端只返回一条消息时在收到该消息后结束，
+// 调用方未读取到流结束就取消上下文时随上下文结束。
+func StreamClientInterceptor(t Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startClientSpan(ctx, t, cc.Target(), method)
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endClientSpan(span, err)
+			span.End()
+			return nil, err
+		}
+		return newTracedClientStream(ctx, stream, desc, span), nil
+	}
+}
+
+// startServerSpan 提取追踪上下文并创建服务端 Span
+func startServerSpan(ctx context.Context, t Tracer, fullMethod string) (context.Context, Span) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		ctx = t.Extract(ctx, transport.MetadataCarrier(md.Copy()))
+	}
+
+	attrs := rpcAttributes(fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, peerAttributes(p.Addr.String())...)
+	}
+	return t.Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		WithSpanKind(SpanKindServer),
+		WithSpanAttributes(attrs...),
+	)
+}
+
+// startClientSpan 创建客户端 Span 并将追踪上下文注入出站元数据
+func startClientSpan(ctx context.Context, t Tracer, target, fullMethod string) (context.Context, Span) {
+	attrs := append(rpcAttributes(fullMethod), semconv.ServerAddress(target))
+	ctx, span := t.Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		WithSpanKind(SpanKindClient),
+		WithSpanAttributes(attrs...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	if err := t.Inject(ctx, transport.MetadataCarrier(md)); err != nil {
+		span.RecordError(err)
+	}
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endServerSpan 记录服务端调用结果，仅服务端错误类状态码将 Span 标记为错误
+func endServerSpan(span Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	switch {
+	case code == codes.OK:
+	case transport.IsGRPCServerError(code):
+		span.SetError(err)
+	default:
+		span.RecordError(err)
+	}
+}
+
+// endClientSpan 记录客户端调用结果，非 OK 状态码将 Span 标记为错误
+func endClientSpan(span Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.SetError(err)
+	}
+}
+
+// rpcAttributes 返回完整方法名对应的 RPC 语义属性
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		attrs = append(attrs, semconv.RPCService(name[:i]), semconv.RPCMethod(name[i+1:]))
+	}
+	return attrs
+}
+
+// peerAttributes 返回对端地址属性
+func peerAttributes(addr string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetworkPeerAddress(addr)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetworkPeerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetworkPeerPort(p))
+	}
+	return attrs
+}
+
+// wrappedServerStream 替换上下文的服务端流
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 实现 grpc.ServerStream 接口
+func (w *wrappedServerStream) Context() context.Context {
+	return w.ctx
+}
+
+// tracedClientStream 在流结束时结束 Span 的客户端流
+type tracedClientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	once          sync.Once
+	stop          func() bool
+	span          Span
+}
+
+// newTracedClientStream 创建客户端流，ctx 结束时结束 Span
+//
+// 使用调用方的上下文而不是 stream.Context()，后者在流正常结束时同样会被取消。
+func newTracedClientStream(ctx context.Context, stream grpc.ClientStream, desc *grpc.StreamDesc, span Span) *tracedClientStream {
+	s := &tracedClientStream{
+		ClientStream:  stream,
+		serverStreams: desc.ServerStreams,
+		span:          span,
+	}
+	s.stop = context.AfterFunc(ctx, func() {
+		s.once.Do(func() {
+			s.finish(status.FromContextError(ctx.Err()).Err())
+		})
+	})
+	return s
+}
+
+// RecvMsg 实现 grpc.ClientStream 接口
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		if !s.serverStreams {
+			s.end(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	default:
+		s.end(err)
+	}
+	return err
+}
+
+// end 停止监听上下文并结束 Span，只执行一次
+func (s *tracedClientStream) end(err error) {
+	s.stop()
+	s.once.Do(func() {
+		s.finish(err)
+	})
+}
+
+// finish 记录调用结果并结束 Span
+func (s *tracedClientStream) finish(err error) {
+	endClientSpan(s.span, err)
+	s.span.End()
+}
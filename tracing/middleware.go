@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Middleware 创建HTTP服务端追踪中间件
+//
+// 从请求头中提取 W3C 追踪上下文并创建服务端 Span，Span 名称为请求方法与路由模板，
+// 例如 GET /v1/users/:id。状态码为 5xx 或处理函数记录了错误时将 Span 标记为错误。
+func Middleware(t Tracer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := t.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			semconv.URLPath(c.Request.URL.Path),
+			semconv.URLScheme(scheme(c.Request)),
+			semconv.ServerAddress(c.Request.Host),
+			semconv.ClientAddress(c.ClientIP()),
+			semconv.UserAgentOriginal(c.Request.UserAgent()),
+		}
+		if route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+
+		ctx, span := t.Start(ctx, name,
+			WithSpanKind(SpanKindServer),
+			WithSpanAttributes(attrs...),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		code := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(code))
+		switch {
+		case len(c.Errors) > 0:
+			span.SetError(c.Errors.Last().Err)
+		case code >= http.StatusInternalServerError:
+			span.SetStatus(1, fmt.Sprintf("HTTP %d", code))
+		}
+	}
+}
+
+// scheme 返回请求协议
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -3,18 +3,20 @@ package tracing
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/huangsc/blade/transport"
+
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type otelTracer struct {
@@ -33,7 +35,7 @@ func NewOTelTracer(opts ...Option) (Tracer, error) {
 		ServiceName:    "unknown",
 		ServiceVersion: "unknown",
 		Environment:    "unknown",
-		Endpoint:       "localhost:4317",
+		Exporter:       ExporterOTLPGRPC,
 		Sampler:        1.0,
 		Timeout:        5 * time.Second,
 		RetryCount:     3,
@@ -44,43 +46,42 @@ func NewOTelTracer(opts ...Option) (Tracer, error) {
 		opt(options)
 	}
 
-	// 创建 OTLP exporter
+	// 创建导出器
 	ctx := context.Background()
-	exp, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint(options.Endpoint),
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithDialOption(grpc.WithBlock()),
-		otlptracegrpc.WithTimeout(options.Timeout),
-		otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
-			Enabled:         true,
-			InitialInterval: options.RetryDelay,
-			MaxInterval:     options.RetryDelay * 2,
-			MaxElapsedTime:  options.Timeout,
-		}),
-	)
+	exp, err := newExporter(ctx, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create exporter: %v", err)
 	}
 
-	// 创建资源
+	// 创建资源，全局属性作为资源属性附加到所有 Span
+	attrs := append([]attribute.KeyValue{
+		semconv.ServiceName(options.ServiceName),
+		semconv.ServiceVersion(options.ServiceVersion),
+		semconv.DeploymentEnvironment(options.Environment),
+	}, options.Attributes...)
 	res, err := resource.Merge(
 		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(options.ServiceName),
-			semconv.ServiceVersion(options.ServiceVersion),
-			semconv.DeploymentEnvironment(options.Environment),
-		),
+		resource.NewWithAttributes(semconv.SchemaURL, attrs...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %v", err)
 	}
 
+	// 创建采样器，默认根 Span 按比例采样，子 Span 跟随父 Span
+	sampler := options.SpanSampler
+	if sampler == nil {
+		sampler = ParentBasedSampler(RatioSampler(options.Sampler))
+	}
+
 	// 创建追踪器提供者
+	processor := sdktrace.NewBatchSpanProcessor(exp, sdktrace.WithExportTimeout(options.Timeout))
+	if options.SyncExport {
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	}
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exp),
+		sdktrace.WithSpanProcessor(processor),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(options.Sampler)),
+		sdktrace.WithSampler(sampler),
 	)
 
 	// 设置全局追踪器提供者
@@ -116,6 +117,7 @@ func (t *otelTracer) Start(ctx context.Context, name string, opts ...SpanOption)
 	spanOpts := []trace.SpanStartOption{
 		trace.WithAttributes(options.Attributes...),
 		trace.WithTimestamp(options.StartTime),
+		trace.WithLinks(options.Links...),
 	}
 
 	switch options.Kind {
@@ -136,24 +138,46 @@ func (t *otelTracer) Start(ctx context.Context, name string, opts ...SpanOption)
 }
 
 func (t *otelTracer) Extract(ctx context.Context, carrier interface{}) context.Context {
-	switch c := carrier.(type) {
-	case propagation.TextMapCarrier:
-		return otel.GetTextMapPropagator().Extract(ctx, c)
-	default:
+	c, ok := textMapCarrier(carrier)
+	if !ok {
 		return ctx
 	}
+	return otel.GetTextMapPropagator().Extract(ctx, c)
 }
 
 func (t *otelTracer) Inject(ctx context.Context, carrier interface{}) error {
+	c, ok := textMapCarrier(carrier)
+	if !ok {
+		return fmt.Errorf("unsupported carrier type: %T", carrier)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, c)
+	return nil
+}
+
+// textMapCarrier 将载体转换为 propagation.TextMapCarrier，支持 http.Header 与 gRPC 元数据
+func textMapCarrier(carrier interface{}) (propagation.TextMapCarrier, bool) {
 	switch c := carrier.(type) {
 	case propagation.TextMapCarrier:
-		otel.GetTextMapPropagator().Inject(ctx, c)
-		return nil
+		return c, true
+	case http.Header:
+		return propagation.HeaderCarrier(c), true
+	case metadata.MD:
+		return transport.MetadataCarrier(c), true
 	default:
-		return fmt.Errorf("unsupported carrier type: %T", carrier)
+		return nil, false
 	}
 }
 
+// ForceFlush 立即导出所有已结束的 Span
+func (t *otelTracer) ForceFlush(ctx context.Context) error {
+	return t.tp.ForceFlush(ctx)
+}
+
+// Shutdown 导出剩余的 Span 并关闭追踪器提供者与导出器
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	return t.tp.Shutdown(ctx)
+}
+
 func (s *otelSpan) End() {
 	s.span.End()
 }
@@ -186,6 +210,10 @@ func (s *otelSpan) AddEvent(name string, attributes ...attribute.KeyValue) {
 	s.span.AddEvent(name, trace.WithAttributes(attributes...))
 }
 
+func (s *otelSpan) AddLink(sc trace.SpanContext, attributes ...attribute.KeyValue) {
+	s.span.AddLink(trace.Link{SpanContext: sc, Attributes: attributes})
+}
+
 func (s *otelSpan) RecordError(err error, opts ...trace.EventOption) {
 	s.span.RecordError(err, opts...)
 }
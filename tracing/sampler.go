@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/huangsc/blade/ratelimit"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingRule 采样规则
+type SamplingRule struct {
+	// SpanName Span 名称，以 * 结尾时按前缀匹配，为空时匹配任意名称
+	SpanName string
+	// AttributeKey 属性键，为空时不匹配属性
+	AttributeKey string
+	// AttributeValue 属性值，为空时只要求属性存在
+	AttributeValue string
+	// Ratio 命中规则时的采样率
+	Ratio float64
+}
+
+// match 判断 Span 是否命中规则
+func (r *SamplingRule) match(p sdktrace.SamplingParameters) bool {
+	if r.SpanName != "" {
+		if prefix, ok := strings.CutSuffix(r.SpanName, "*"); ok {
+			if !strings.HasPrefix(p.Name, prefix) {
+				return false
+			}
+		} else if r.SpanName != p.Name {
+			return false
+		}
+	}
+	if r.AttributeKey == "" {
+		return true
+	}
+	for _, kv := range p.Attributes {
+		if string(kv.Key) == r.AttributeKey {
+			return r.AttributeValue == "" || kv.Value.Emit() == r.AttributeValue
+		}
+	}
+	return false
+}
+
+// ParentBasedSampler 创建基于父 Span 的采样器，根 Span 使用 root 采样
+func ParentBasedSampler(root sdktrace.Sampler) sdktrace.Sampler {
+	return sdktrace.ParentBased(root)
+}
+
+// RatioSampler 创建按 TraceID 比例采样的采样器
+func RatioSampler(ratio float64) sdktrace.Sampler {
+	return sdktrace.TraceIDRatioBased(ratio)
+}
+
+// ruleSampler 基于规则的采样器
+type ruleSampler struct {
+	rules    []SamplingRule
+	samplers []sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+// RuleSampler 创建基于规则的采样器
+//
+// 按顺序匹配 Span 名称与创建时的属性，使用首个命中规则的采样率，
+// 均未命中时使用 fallback，fallback 为 nil 时全部采样。
+func RuleSampler(fallback sdktrace.Sampler, rules ...SamplingRule) sdktrace.Sampler {
+	if fallback == nil {
+		fallback = sdktrace.AlwaysSample()
+	}
+	samplers := make([]sdktrace.Sampler, len(rules))
+	for i, r := range rules {
+		samplers[i] = sdktrace.TraceIDRatioBased(r.Ratio)
+	}
+	return &ruleSampler{
+		rules:    rules,
+		samplers: samplers,
+		fallback: fallback,
+	}
+}
+
+// ShouldSample 实现 sdktrace.Sampler 接口
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for i := range s.rules {
+		if s.rules[i].match(p) {
+			return s.samplers[i].ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description 实现 sdktrace.Sampler 接口
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+// rateLimitedSampler 限流采样器
+type rateLimitedSampler struct {
+	rate    float64
+	limiter ratelimit.Limiter
+}
+
+// RateLimitedSampler 创建限流采样器，每秒最多采样 perSecond 个 Span
+func RateLimitedSampler(perSecond float64) sdktrace.Sampler {
+	return &rateLimitedSampler{
+		rate: perSecond,
+		limiter: ratelimit.NewTokenBucket(
+			ratelimit.WithRate(perSecond),
+			ratelimit.WithBurst(int64(math.Max(1, math.Ceil(perSecond)))),
+		),
+	}
+}
+
+// ShouldSample 实现 sdktrace.Sampler 接口
+func (s *rateLimitedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+	if ok, err := s.limiter.Allow(context.Background(), "", 1); err == nil && ok {
+		result.Decision = sdktrace.RecordAndSample
+	}
+	return result
+}
+
+// Description 实现 sdktrace.Sampler 接口
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%g}", s.rate)
+}
@@ -2,9 +2,11 @@ package tracing
 
 import (
 	"context"
+	"crypto/tls"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -30,10 +32,14 @@ const (
 type Tracer interface {
 	// Start 开始一个新的 Span
 	Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, Span)
-	// Extract 从载体中提取上下文
+	// Extract 从载体中提取上下文，载体可以是 propagation.TextMapCarrier、http.Header 或 metadata.MD
 	Extract(ctx context.Context, carrier interface{}) context.Context
-	// Inject 注入上下文到载体
+	// Inject 注入上下文到载体，载体可以是 propagation.TextMapCarrier、http.Header 或 metadata.MD
 	Inject(ctx context.Context, carrier interface{}) error
+	// ForceFlush 立即导出所有已结束的 Span
+	ForceFlush(ctx context.Context) error
+	// Shutdown 导出剩余的 Span 并关闭追踪器
+	Shutdown(ctx context.Context) error
 }
 
 // Span 表示一个追踪片段
@@ -50,6 +56,8 @@ type Span interface {
 	SetAttributes(kv ...attribute.KeyValue)
 	// AddEvent 添加事件
 	AddEvent(name string, attributes ...attribute.KeyValue)
+	// AddLink 添加指向其他 Span 的链接
+	AddLink(sc trace.SpanContext, attributes ...attribute.KeyValue)
 	// RecordError 记录错误
 	RecordError(err error, opts ...trace.EventOption)
 	// SpanContext 获取 Span 上下文
@@ -71,6 +79,8 @@ type SpanOptions struct {
 	StartTime time.Time
 	// Kind Span 的类型
 	Kind SpanKind
+	// Links 指向其他 Span 的链接，例如批量消费时链接每条消息的生产者 Span
+	Links []trace.Link
 }
 
 // WithSpanAttributes 设置 Span 属性
@@ -87,6 +97,17 @@ func WithStartTime(t time.Time) SpanOption {
 	}
 }
 
+// WithLinks 添加指向其他 Span 的链接
+func WithLinks(scs ...trace.SpanContext) SpanOption {
+	return func(o *SpanOptions) {
+		for _, sc := range scs {
+			if sc.IsValid() {
+				o.Links = append(o.Links, trace.Link{SpanContext: sc})
+			}
+		}
+	}
+}
+
 // WithSpanKind 设置 Span 类型
 func WithSpanKind(kind SpanKind) SpanOption {
 	return func(o *SpanOptions) {
@@ -105,10 +126,24 @@ type Options struct {
 	ServiceVersion string
 	// Environment 环境
 	Environment string
-	// Endpoint 端点
+	// Endpoint 端点，为空时使用导出器的默认端点
 	Endpoint string
-	// Sampler 采样器
+	// Exporter 导出器类型，默认为 ExporterOTLPGRPC
+	Exporter ExporterKind
+	// SpanExporter 自定义导出器，设置后忽略 Exporter
+	SpanExporter sdktrace.SpanExporter
+	// TLSConfig OTLP 导出器的TLS配置，为 nil 时使用非安全连接
+	TLSConfig *tls.Config
+	// Headers OTLP 导出器的请求头
+	Headers map[string]string
+	// FilePath 文件导出器的输出路径
+	FilePath string
+	// SyncExport 是否在 Span 结束时同步导出，默认批量异步导出
+	SyncExport bool
+	// Sampler 采样率，根 Span 按该比例采样，子 Span 跟随父 Span
 	Sampler float64
+	// SpanSampler 自定义采样器，设置后忽略 Sampler
+	SpanSampler sdktrace.Sampler
 	// Attributes 全局属性
 	Attributes []attribute.KeyValue
 	// Timeout 超时时间
@@ -147,13 +182,62 @@ func WithEndpoint(endpoint string) Option {
 	}
 }
 
-// WithSampler 设置采样器
+// WithExporter 设置导出器类型
+func WithExporter(kind ExporterKind) Option {
+	return func(o *Options) {
+		o.Exporter = kind
+	}
+}
+
+// WithSpanExporter 设置自定义导出器，例如 NewMemoryExporter 创建的内存导出器
+func WithSpanExporter(exporter sdktrace.SpanExporter) Option {
+	return func(o *Options) {
+		o.SpanExporter = exporter
+	}
+}
+
+// WithTLSConfig 设置 OTLP 导出器的TLS配置
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
+// WithHeaders 设置 OTLP 导出器的请求头
+func WithHeaders(headers map[string]string) Option {
+	return func(o *Options) {
+		o.Headers = headers
+	}
+}
+
+// WithFilePath 设置文件导出器的输出路径
+func WithFilePath(path string) Option {
+	return func(o *Options) {
+		o.FilePath = path
+	}
+}
+
+// WithSyncExport 设置是否在 Span 结束时同步导出，适用于测试中配合内存导出器使用
+func WithSyncExport(sync bool) Option {
+	return func(o *Options) {
+		o.SyncExport = sync
+	}
+}
+
+// WithSampler 设置采样率
 func WithSampler(sampler float64) Option {
 	return func(o *Options) {
 		o.Sampler = sampler
 	}
 }
 
+// WithSpanSampler 设置自定义采样器，可使用 ParentBasedSampler、RuleSampler 与 RateLimitedSampler 组合
+func WithSpanSampler(sampler sdktrace.Sampler) Option {
+	return func(o *Options) {
+		o.SpanSampler = sampler
+	}
+}
+
 // WithGlobalAttributes 设置全局属性
 func WithGlobalAttributes(kv ...attribute.KeyValue) Option {
 	return func(o *Options) {
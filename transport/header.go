@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// HeaderCarrier 基于 http.Header 的头部载体
+type HeaderCarrier http.Header
+
+// Get 获取键对应的第一个值
+func (h HeaderCarrier) Get(key string) string {
+	return http.Header(h).Get(key)
+}
+
+// Set 设置键的值
+func (h HeaderCarrier) Set(key, value string) {
+	http.Header(h).Set(key, value)
+}
+
+// Add 为键追加值
+func (h HeaderCarrier) Add(key, value string) {
+	http.Header(h).Add(key, value)
+}
+
+// Values 获取键对应的所有值
+func (h HeaderCarrier) Values(key string) []string {
+	return http.Header(h).Values(key)
+}
+
+// Keys 返回所有键
+func (h HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// MetadataCarrier 基于 gRPC 元数据的头部载体，键不区分大小写
+type MetadataCarrier metadata.MD
+
+// Get 获取键对应的第一个值
+func (m MetadataCarrier) Get(key string) string {
+	if v := metadata.MD(m).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set 设置键的值
+func (m MetadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+// Add 为键追加值
+func (m MetadataCarrier) Add(key, value string) {
+	metadata.MD(m).Append(key, value)
+}
+
+// Values 获取键对应的所有值
+func (m MetadataCarrier) Values(key string) []string {
+	return metadata.MD(m).Get(key)
+}
+
+// Keys 返回所有键
+func (m MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,15 @@
+package transport
+
+import "google.golang.org/grpc/codes"
+
+// IsGRPCServerError 判断 gRPC 状态码是否为服务端错误
+//
+// 服务端错误计入错误指标并将 Span 标记为错误，其余非 OK 状态码视为调用方错误。
+func IsGRPCServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
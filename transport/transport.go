@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+)
+
+// Kind 传输类型
+type Kind string
+
+const (
+	// KindHTTP HTTP传输
+	KindHTTP Kind = "http"
+	// KindGRPC gRPC传输
+	KindGRPC Kind = "grpc"
+	// KindKafka Kafka传输
+	KindKafka Kind = "kafka"
+)
+
+// String 返回传输类型名称
+func (k Kind) String() string {
+	return string(k)
+}
+
+// Header 请求头或响应头载体
+type Header interface {
+	// Get 获取键对应的第一个值
+	Get(key string) string
+	// Set 设置键的值
+	Set(key, value string)
+	// Add 为键追加值
+	Add(key, value string)
+	// Values 获取键对应的所有值
+	Values(key string) []string
+	// Keys 返回所有键
+	Keys() []string
+}
+
+// Transporter 传输层信息
+type Transporter interface {
+	// Kind 返回传输类型
+	Kind() Kind
+	// Endpoint 返回端点地址，服务端为对端地址，客户端为目标地址
+	Endpoint() string
+	// Operation 返回操作名称，HTTP 为路由模板，gRPC 为完整方法名，Kafka 为主题
+	Operation() string
+	// RequestHeader 返回请求头
+	RequestHeader() Header
+	// ReplyHeader 返回响应头
+	ReplyHeader() Header
+}
+
+type (
+	serverTransportKey struct{}
+	clientTransportKey struct{}
+)
+
+// NewServerContext 返回携带服务端传输信息的上下文
+func NewServerContext(ctx context.Context, tr Transporter) context.Context {
+	return context.WithValue(ctx, serverTransportKey{}, tr)
+}
+
+// FromServerContext 从上下文中获取服务端传输信息
+func FromServerContext(ctx context.Context) (Transporter, bool) {
+	tr, ok := ctx.Value(serverTransportKey{}).(Transporter)
+	return tr, ok
+}
+
+// NewClientContext 返回携带客户端传输信息的上下文
+func NewClientContext(ctx context.Context, tr Transporter) context.Context {
+	return context.WithValue(ctx, clientTransportKey{}, tr)
+}
+
+// FromClientContext 从上下文中获取客户端传输信息
+func FromClientContext(ctx context.Context) (Transporter, bool) {
+	tr, ok := ctx.Value(clientTransportKey{}).(Transporter)
+	return tr, ok
+}